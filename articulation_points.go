@@ -0,0 +1,156 @@
+package goraph
+
+import "sort"
+
+// ArticulationPoints returns the sorted IDs of the nodes whose removal
+// increases the number of weakly connected components, using the
+// low-link depth-first search of Hopcroft and Tarjan. The graph is
+// treated as undirected, where edges in both directions between two
+// nodes count as one edge, and self-loops are ignored.
+// (https://en.wikipedia.org/wiki/Biconnected_component)
+//
+//	 0. ArticulationPoints(G)
+//	 1.
+//	 2. 	for each vertex v in G:
+//	 3. 		if v.index is undefined:
+//	 4. 			lowLink(G, v, nil)
+//	 5.
+//	 6.
+//	 7. lowLink(G, v, parent):
+//	 8.
+//	 9. 	v.index = v.low = globalIndex
+//	10. 	globalIndex++
+//	11.
+//	12. 	for each vertex w adjacent to v:
+//	13.
+//	14. 		if w.index is undefined:
+//	15. 			lowLink(G, w, v)
+//	16. 			v.low = min(v.low, w.low)
+//	17.
+//	18. 			if parent is not nil and w.low >= v.index:
+//	19. 				v is an articulation point
+//	20. 			if w.low > v.index:
+//	21. 				(v, w) is a bridge
+//	22.
+//	23. 		else if w is not parent:
+//	24. 			v.low = min(v.low, w.index)
+//	25.
+//	26. 	if parent is nil and v has more than one child in the DFS tree:
+//	27. 		v is an articulation point
+//
+func (g *graph) ArticulationPoints() []ID {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	d := g.unsafeLowLink()
+	rs := []ID{}
+	for id := range d.cut {
+		rs = append(rs, id)
+	}
+	sort.Sort(idSlice(rs))
+	return rs
+}
+
+// Bridges returns the edges whose removal increases the number of
+// weakly connected components, with the same low-link depth-first
+// search as ArticulationPoints. When the nodes of a bridge are
+// connected in both directions, the edge from the smaller ID is
+// returned. The edges are sorted by source and target IDs.
+func (g *graph) Bridges() []Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	d := g.unsafeLowLink()
+	rs := []Edge{}
+	for _, b := range d.bridges {
+		u, v := b[0], b[1]
+		if lessID(v, u) {
+			u, v = v, u
+		}
+		if _, ok := g.nodeChildren[u][v]; !ok {
+			u, v = v, u
+		}
+		rs = append(rs, NewEdge(g.nodes[u], g.nodes[v], g.nodeChildren[u][v], nil))
+	}
+	sortEdges(rs)
+	return rs
+}
+
+type lowLinkData struct {
+	// adj is the undirected adjacency without self-loops.
+	adj map[ID]map[ID]struct{}
+
+	globalIndex int
+	index       map[ID]int
+	low         map[ID]int
+
+	// cut is the set of articulation points.
+	cut map[ID]struct{}
+
+	bridges [][2]ID
+}
+
+func (g *graph) unsafeLowLink() *lowLinkData {
+	d := &lowLinkData{
+		adj:   make(map[ID]map[ID]struct{}, len(g.nodes)),
+		index: make(map[ID]int, len(g.nodes)),
+		low:   make(map[ID]int, len(g.nodes)),
+		cut:   make(map[ID]struct{}),
+	}
+	for id := range g.nodes {
+		d.adj[id] = make(map[ID]struct{})
+	}
+	for id1, cmap := range g.nodeChildren {
+		for id2 := range cmap {
+			if id1 != id2 {
+				d.adj[id1][id2] = struct{}{}
+				d.adj[id2][id1] = struct{}{}
+			}
+		}
+	}
+
+	// for each vertex v in G:
+	for _, v := range sortedIDs(g.nodes) {
+		// if v.index is undefined:
+		if _, ok := d.index[v]; !ok {
+			lowLink(d, v, nil)
+		}
+	}
+	return d
+}
+
+func lowLink(d *lowLinkData, v ID, parent ID) {
+	d.index[v] = d.globalIndex
+	d.low[v] = d.globalIndex
+	d.globalIndex++
+
+	children := 0
+
+	// for each vertex w adjacent to v:
+	for w := range d.adj[v] {
+		// if w.index is undefined:
+		if _, ok := d.index[w]; !ok {
+			children++
+			lowLink(d, w, v)
+			if d.low[w] < d.low[v] {
+				d.low[v] = d.low[w]
+			}
+
+			if parent != nil && d.low[w] >= d.index[v] {
+				d.cut[v] = struct{}{}
+			}
+			if d.low[w] > d.index[v] {
+				d.bridges = append(d.bridges, [2]ID{v, w})
+			}
+
+		} else if w != parent {
+			if d.index[w] < d.low[v] {
+				d.low[v] = d.index[w]
+			}
+		}
+	}
+
+	if parent == nil && children > 1 {
+		d.cut[v] = struct{}{}
+	}
+}
@@ -0,0 +1,55 @@
+package goraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraph_ArticulationPoints(t *testing.T) {
+	// two triangles A-B-C and D-E-F joined by the bridge C-D,
+	// with a pendant G on F and an isolated H
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D", "E", "F", "G", "H"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	for _, e := range [][2]string{
+		{"A", "B"}, {"B", "C"}, {"C", "A"},
+		{"D", "E"}, {"E", "F"}, {"F", "D"},
+		{"D", "C"}, {"C", "D"},
+		{"F", "G"},
+		{"H", "H"},
+	} {
+		g.AddEdge(StringID(e[0]), StringID(e[1]), 1)
+	}
+
+	if rs := g.ArticulationPoints(); !reflect.DeepEqual(rs, []ID{StringID("C"), StringID("D"), StringID("F")}) {
+		t.Fatalf("Expected [C D F] but %v", rs)
+	}
+	if rs := edgeStrings(g.Bridges()); !reflect.DeepEqual(rs, []string{"C -- 1.000 -→ D\n", "F -- 1.000 -→ G\n"}) {
+		t.Fatalf("Expected [C->D F->G] but %v", rs)
+	}
+
+	// closing the cycle removes the bridge
+	g.AddEdge(StringID("A"), StringID("E"), 1)
+	g.DeleteNode(StringID("G"))
+	if rs := g.ArticulationPoints(); len(rs) != 0 {
+		t.Fatalf("Expected no articulation points but %v", rs)
+	}
+	if rs := g.Bridges(); len(rs) != 0 {
+		t.Fatalf("Expected no bridges but %v", edgeStrings(rs))
+	}
+
+	// the root of the search is a cut vertex with two children
+	g = NewGraph()
+	for _, id := range []string{"A", "B", "C"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	g.AddEdge(StringID("B"), StringID("A"), 2)
+	g.AddEdge(StringID("A"), StringID("C"), 3)
+	if rs := g.ArticulationPoints(); !reflect.DeepEqual(rs, []ID{StringID("A")}) {
+		t.Fatalf("Expected [A] but %v", rs)
+	}
+	if rs := edgeStrings(g.Bridges()); !reflect.DeepEqual(rs, []string{"A -- 3.000 -→ C\n", "B -- 2.000 -→ A\n"}) {
+		t.Fatalf("Expected [A->C B->A] but %v", rs)
+	}
+}
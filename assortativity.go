@@ -0,0 +1,59 @@
+package goraph
+
+import (
+	"fmt"
+	"math"
+)
+
+// DegreeAssortativity returns the Pearson correlation coefficient of
+// the degrees at the two ends of the edges of the graph treated as
+// undirected and unweighted, where edges in both directions between
+// two nodes count once and self-loops are ignored. Each edge {u, v}
+// contributes the pairs (deg(u), deg(v)) and (deg(v), deg(u)), so the
+// value is symmetric and in [-1, 1]: positive when high-degree nodes
+// link to each other, and negative when they link to low-degree nodes.
+// It returns NaN and an error when the coefficient is undefined, that
+// is, with fewer than two edges or when all edges join nodes of the
+// same degree.
+// (https://en.wikipedia.org/wiki/Assortativity)
+func (g *graph) DegreeAssortativity() (float64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	neighbors := make(map[ID]map[ID]bool, len(g.nodes))
+	for id := range g.nodes {
+		neighbors[id] = make(map[ID]bool)
+	}
+	for id1, tmap := range g.nodeChildren {
+		for id2 := range tmap {
+			if id1 == id2 {
+				continue
+			}
+			neighbors[id1][id2] = true
+			neighbors[id2][id1] = true
+		}
+	}
+
+	// each edge is visited from both ends, so the degrees at either
+	// end have the same mean and variance, which are computed once
+	var n, sum, sumSq, sumProd float64
+	for _, nmap := range neighbors {
+		du := float64(len(nmap))
+		for v := range nmap {
+			dv := float64(len(neighbors[v]))
+			n++
+			sum += du
+			sumSq += du * du
+			sumProd += du * dv
+		}
+	}
+	if n < 4 {
+		return math.NaN(), fmt.Errorf("assortativity is undefined for %d edges", int(n)/2)
+	}
+	mean := sum / n
+	variance := sumSq/n - mean*mean
+	if variance <= 1e-12 {
+		return math.NaN(), fmt.Errorf("assortativity is undefined when all nodes of the edges have the same degree")
+	}
+	return (sumProd/n - mean*mean) / variance, nil
+}
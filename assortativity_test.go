@@ -0,0 +1,58 @@
+package goraph
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGraph_DegreeAssortativity(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D"} {
+		g.AddNode(NewNode(id, nil))
+	}
+
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	if r, err := g.DegreeAssortativity(); err == nil || !math.IsNaN(r) {
+		t.Fatalf("Expected NaN and an error for one edge but %f, %v", r, err)
+	}
+
+	// the path A-B-C-D with degrees 1, 2, 2, 1. The ends of the edges
+	// in both directions are (1,2) (2,1) (2,2) (2,2) (2,1) (1,2), with
+	// mean 5/3, variance 2/9 and covariance -1/9, so r = -1/2.
+	g.AddEdge(StringID("C"), StringID("B"), 1)
+	g.AddEdge(StringID("C"), StringID("D"), 1)
+	// the reverse edge and a self-loop do not count
+	g.AddEdge(StringID("B"), StringID("A"), 5)
+	g.AddEdge(StringID("D"), StringID("D"), 1)
+	r, err := g.DegreeAssortativity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(r-(-0.5)) > 1e-9 {
+		t.Fatalf("Expected -0.5 but %f", r)
+	}
+
+	// a star only joins the hub to leaves
+	s := NewGraph()
+	for _, id := range []string{"H", "A", "B", "C"} {
+		s.AddNode(NewNode(id, nil))
+	}
+	for _, id := range []string{"A", "B", "C"} {
+		s.AddEdge(StringID("H"), StringID(id), 1)
+	}
+	r, err = s.DegreeAssortativity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(r-(-1)) > 1e-9 {
+		t.Fatalf("Expected -1 but %f", r)
+	}
+
+	// every node of a cycle has the same degree
+	s.AddEdge(StringID("A"), StringID("B"), 1)
+	s.AddEdge(StringID("B"), StringID("C"), 1)
+	s.AddEdge(StringID("C"), StringID("A"), 1)
+	if r, err := s.DegreeAssortativity(); err == nil || !math.IsNaN(r) {
+		t.Fatalf("Expected NaN and an error for a regular graph but %f, %v", r, err)
+	}
+}
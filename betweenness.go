@@ -0,0 +1,128 @@
+package goraph
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// BetweennessCentrality returns the betweenness of every node, the sum
+// over all ordered pairs of other nodes (s, t) of the fraction of the
+// shortest paths from s to t that pass through the node, following
+// the direction of the edges. It uses Brandes' algorithm with Dijkstra's
+// shortest paths, and returns error if an edge other than a self-loop
+// has a weight that is not positive, since zero-weight edges make the
+// order of the nodes at the same distance ambiguous.
+// Pairs that cannot reach each other contribute nothing. The scores
+// are not normalized. Time complexity is O(|V||E| + |V|^2 log|V|).
+// (https://en.wikipedia.org/wiki/Betweenness_centrality)
+//
+//	 0. Brandes(G)
+//	 1.
+//	 2. 	for each vertex v in G:
+//	 3. 		C[v] = 0
+//	 4.
+//	 5. 	for each vertex s in G:
+//	 6.
+//	 7. 		S = empty stack
+//	 8. 		P[w] = empty list for each w
+//	 9. 		sigma[s] = 1 and 0 for others
+//	10.
+//	11. 		Dijkstra from s, and for each vertex v popped:
+//	12. 			S.push(v)
+//	13. 			for each child vertex w of v:
+//	14. 				if dist[w] > dist[v] + weight(v, w):
+//	15. 					dist[w] = dist[v] + weight(v, w)
+//	16. 					sigma[w] = sigma[v]
+//	17. 					P[w] = [v]
+//	18. 				else if dist[w] == dist[v] + weight(v, w):
+//	19. 					sigma[w] += sigma[v]
+//	20. 					P[w].append(v)
+//	21.
+//	22. 		delta[v] = 0 for each v
+//	23. 		while S is not empty:
+//	24. 			w = S.pop()
+//	25. 			for v in P[w]:
+//	26. 				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+//	27. 			if w != s:
+//	28. 				C[w] += delta[w]
+//
+func BetweennessCentrality(g Graph) (map[ID]float64, error) {
+	if err := checkNonNegative(g); err != nil {
+		return nil, err
+	}
+
+	nodes := g.Nodes()
+	children := make(map[ID]map[ID]float64, len(nodes))
+	for _, edge := range g.Edges() {
+		src, tgt := edge.Source().ID(), edge.Target().ID()
+		if src == tgt {
+			continue
+		}
+		if edge.Weight() == 0 {
+			return nil, fmt.Errorf("weight from %s to %s must be positive but %f", src, tgt, edge.Weight())
+		}
+		if _, ok := children[src]; !ok {
+			children[src] = make(map[ID]float64)
+		}
+		children[src][tgt] = edge.Weight()
+	}
+
+	// for each vertex v in G:
+	rs := make(map[ID]float64, len(nodes))
+	for v := range nodes {
+		// C[v] = 0
+		rs[v] = 0
+	}
+
+	// for each vertex s in G:
+	for s := range nodes {
+		stack := []ID{}
+		prev := make(map[ID][]ID)
+		sigma := map[ID]float64{s: 1}
+		distance := map[ID]float64{s: 0}
+		visited := make(map[ID]bool)
+
+		minHeap := &nodeDistanceHeap{}
+		heap.Push(minHeap, nodeDistance{id: s, distance: 0})
+		for minHeap.Len() != 0 {
+			v := heap.Pop(minHeap).(nodeDistance)
+			if visited[v.id] {
+				continue
+			}
+			visited[v.id] = true
+
+			// S.push(v)
+			stack = append(stack, v.id)
+
+			// for each child vertex w of v:
+			for w, weight := range children[v.id] {
+				alt := distance[v.id] + weight
+				d, ok := distance[w]
+				switch {
+				case !ok || d > alt:
+					distance[w] = alt
+					sigma[w] = sigma[v.id]
+					prev[w] = []ID{v.id}
+					heap.Push(minHeap, nodeDistance{id: w, distance: alt})
+				case d == alt:
+					sigma[w] += sigma[v.id]
+					prev[w] = append(prev[w], v.id)
+				}
+			}
+		}
+
+		// while S is not empty:
+		delta := make(map[ID]float64)
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range prev[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s {
+				rs[w] += delta[w]
+			}
+		}
+	}
+
+	return rs, nil
+}
@@ -0,0 +1,59 @@
+package goraph
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBetweennessCentrality(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D", "S", "X", "Y", "T", "Z"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	// a chain, where B and C lie on 2 shortest paths each
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("B"), StringID("C"), 2)
+	g.AddEdge(StringID("C"), StringID("D"), 1)
+	g.AddEdge(StringID("A"), StringID("D"), 10)
+	// a diamond in another component, where X and Y
+	// split the two shortest paths from S to T
+	g.AddEdge(StringID("S"), StringID("X"), 1)
+	g.AddEdge(StringID("S"), StringID("Y"), 2)
+	g.AddEdge(StringID("X"), StringID("T"), 2)
+	g.AddEdge(StringID("Y"), StringID("T"), 1)
+	g.AddEdge(StringID("T"), StringID("T"), 1)
+
+	rs, err := BetweennessCentrality(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[ID]float64{
+		StringID("A"): 0, StringID("B"): 2, StringID("C"): 2, StringID("D"): 0,
+		StringID("S"): 0, StringID("X"): 0.5, StringID("Y"): 0.5, StringID("T"): 0,
+		StringID("Z"): 0,
+	}
+	if len(rs) != len(expected) {
+		t.Fatalf("Expected %v but %v", expected, rs)
+	}
+	for id, v := range expected {
+		if math.Abs(rs[id]-v) > EqualEpsilon {
+			t.Fatalf("Expected %s to be %f but %f", id, v, rs[id])
+		}
+	}
+
+	// a zero-weight self-loop is ignored like other self-loops
+	g.AddEdge(StringID("Z"), StringID("Z"), 0)
+	if _, err := BetweennessCentrality(g); err != nil {
+		t.Fatal(err)
+	}
+
+	g.AddEdge(StringID("Z"), StringID("A"), 0)
+	if _, err := BetweennessCentrality(g); err == nil {
+		t.Fatal("Expected error for a zero weight")
+	}
+
+	g.ReplaceEdge(StringID("Z"), StringID("A"), -1)
+	if _, err := BetweennessCentrality(g); err == nil {
+		t.Fatal("Expected error for a negative weight")
+	}
+}
@@ -0,0 +1,77 @@
+package goraph
+
+import "fmt"
+
+// IsBipartite tries to 2-color the graph with breadth-first search,
+// treating edges as undirected. Each weakly connected component is
+// colored separately, starting from its smallest node ID with color 0.
+// It returns true and the color (0 or 1) of every node if the graph is
+// bipartite. Otherwise it returns false, the colors assigned so far
+// including the conflicting component, and an error wrapping
+// ErrNotBipartite with the edge whose ends got the same color.
+// A self-loop always makes the graph non-bipartite.
+// (https://en.wikipedia.org/wiki/Bipartite_graph)
+//
+//	 0. IsBipartite(G):
+//	 1.
+//	 2. 	for each vertex s in G:
+//	 3. 		if s is not colored yet:
+//	 4. 			color[s] = 0
+//	 5. 			let Q be a queue
+//	 6. 			Q.push(s)
+//	 7.
+//	 8. 			while Q is not empty:
+//	 9. 				u = Q.dequeue()
+//	10.
+//	11. 				for each vertex w adjacent to u:
+//	12. 					if w is not colored yet:
+//	13. 						color[w] = 1 - color[u]
+//	14. 						Q.push(w)
+//	15. 					else if color[w] == color[u]:
+//	16. 						return false
+//	17.
+//	18. 	return true
+//
+func (g *graph) IsBipartite() (bool, map[ID]int, error) {
+	color := make(map[ID]int)
+
+	// for each vertex s in G:
+	for _, s := range sortedIDs(g.Nodes()) {
+		// if s is not colored yet:
+		if _, ok := color[s]; ok {
+			continue
+		}
+		color[s] = 0
+		q := []ID{s}
+
+		// while Q is not empty:
+		for len(q) != 0 {
+			u := q[0]
+			q = q[1:len(q):len(q)]
+
+			cmap, err := g.ChildNodesOf(u)
+			if err != nil {
+				return false, nil, err
+			}
+			pmap, err := g.ParentNodesOf(u)
+			if err != nil {
+				return false, nil, err
+			}
+
+			// for each vertex w adjacent to u:
+			for _, adj := range []map[ID]Node{cmap, pmap} {
+				for _, w := range sortedIDs(adj) {
+					c, ok := color[w]
+					if !ok {
+						color[w] = 1 - color[u]
+						q = append(q, w)
+					} else if c == color[u] {
+						return false, color, fmt.Errorf("%w: %s and %s are adjacent with the same color", ErrNotBipartite, u, w)
+					}
+				}
+			}
+		}
+	}
+
+	return true, color, nil
+}
@@ -0,0 +1,54 @@
+package goraph
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGraph_IsBipartite(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D", "X", "Y", "Z"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	// A and C on one side, B and D on the other
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("C"), StringID("B"), 1)
+	g.AddEdge(StringID("C"), StringID("D"), 1)
+	g.AddEdge(StringID("D"), StringID("A"), 1)
+	// a separate component
+	g.AddEdge(StringID("X"), StringID("Y"), 1)
+
+	ok, color, err := g.IsBipartite()
+	if !ok || err != nil {
+		t.Fatalf("Expected bipartite but %v", err)
+	}
+	expected := map[ID]int{
+		StringID("A"): 0, StringID("B"): 1, StringID("C"): 0, StringID("D"): 1,
+		StringID("X"): 0, StringID("Y"): 1, StringID("Z"): 0,
+	}
+	for id, c := range expected {
+		if color[id] != c {
+			t.Fatalf("Expected color %d for %s but %v", c, id, color)
+		}
+	}
+
+	// a triangle in the second component
+	g.AddEdge(StringID("Y"), StringID("Z"), 1)
+	g.AddEdge(StringID("Z"), StringID("X"), 1)
+	ok, _, err = g.IsBipartite()
+	if ok || !errors.Is(err, ErrNotBipartite) {
+		t.Fatalf("Expected ErrNotBipartite but %v %v", ok, err)
+	}
+
+	// a self-loop
+	g = NewGraph()
+	g.AddNode(NewNode("A", nil))
+	g.AddEdge(StringID("A"), StringID("A"), 1)
+	if ok, _, err := g.IsBipartite(); ok || !errors.Is(err, ErrNotBipartite) {
+		t.Fatalf("Expected ErrNotBipartite but %v %v", ok, err)
+	}
+
+	if ok, color, err := NewGraph().IsBipartite(); !ok || len(color) != 0 || err != nil {
+		t.Fatalf("Expected an empty graph to be bipartite but %v %v %v", ok, color, err)
+	}
+}
@@ -0,0 +1,34 @@
+package goraph
+
+// Centrality holds the degree-based centrality measures of a node.
+// Weighted degrees are the sums of the edge weights.
+type Centrality struct {
+	InDegree    int
+	OutDegree   int
+	WeightedIn  float64
+	WeightedOut float64
+}
+
+func (g *graph) CentralityReport() map[ID]Centrality {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	rs := make(map[ID]Centrality, len(g.nodes))
+	for id := range g.nodes {
+		rs[id] = Centrality{}
+	}
+	for id1, tmap := range g.nodeChildren {
+		for id2, weight := range tmap {
+			c1 := rs[id1]
+			c1.OutDegree++
+			c1.WeightedOut += weight
+			rs[id1] = c1
+
+			c2 := rs[id2]
+			c2.InDegree++
+			c2.WeightedIn += weight
+			rs[id2] = c2
+		}
+	}
+	return rs
+}
@@ -0,0 +1,34 @@
+package goraph
+
+import "testing"
+
+func TestGraph_CentralityReport(t *testing.T) {
+	g := NewGraph()
+	if rs := g.CentralityReport(); len(rs) != 0 {
+		t.Fatalf("Expected empty report but %v", rs)
+	}
+
+	for _, id := range []string{"A", "B", "C", "D"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("A"), StringID("C"), 2.5)
+	g.AddEdge(StringID("B"), StringID("C"), 3)
+	g.AddEdge(StringID("C"), StringID("A"), 4)
+
+	expected := map[ID]Centrality{
+		StringID("A"): {InDegree: 1, OutDegree: 2, WeightedIn: 4, WeightedOut: 3.5},
+		StringID("B"): {InDegree: 1, OutDegree: 1, WeightedIn: 1, WeightedOut: 3},
+		StringID("C"): {InDegree: 2, OutDegree: 1, WeightedIn: 5.5, WeightedOut: 4},
+		StringID("D"): {},
+	}
+	rs := g.CentralityReport()
+	if len(rs) != len(expected) {
+		t.Fatalf("Expected %d nodes but %v", len(expected), rs)
+	}
+	for id, c := range expected {
+		if rs[id] != c {
+			t.Fatalf("Expected %+v for %s but %+v", c, id, rs[id])
+		}
+	}
+}
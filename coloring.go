@@ -0,0 +1,99 @@
+package goraph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// GreedyColoring colors the nodes with the Welsh–Powell heuristic,
+// treating edges as undirected, so that adjacent nodes get different
+// colors. Nodes are visited in descending order of degree, counting
+// each neighbor once, with ties broken by ID. It returns the color
+// (0, 1, ...) of every node and the number of colors used, which is
+// at most one more than the maximum degree. It returns error wrapping
+// ErrSelfLoop if a node is adjacent to itself.
+// (https://en.wikipedia.org/wiki/Greedy_coloring)
+//
+//	 0. GreedyColoring(G):
+//	 1.
+//	 2. 	order = vertices of G sorted by descending degree
+//	 3. 	c = 0
+//	 4.
+//	 5. 	while some vertex is not colored:
+//	 6.
+//	 7. 		for each vertex u in order:
+//	 8. 			if u is not colored and
+//	 9. 			   no vertex adjacent to u has color c:
+//	10. 				color[u] = c
+//	11.
+//	12. 		c = c + 1
+//	13.
+//	14. 	return color, c
+//
+func GreedyColoring(g Graph) (map[ID]int, int, error) {
+	adjacent := make(map[ID]map[ID]bool)
+	for id := range g.Nodes() {
+		cmap, err := g.ChildNodesOf(id)
+		if err != nil {
+			return nil, 0, err
+		}
+		pmap, err := g.ParentNodesOf(id)
+		if err != nil {
+			return nil, 0, err
+		}
+		adj := make(map[ID]bool, len(cmap)+len(pmap))
+		for _, m := range []map[ID]Node{cmap, pmap} {
+			for w := range m {
+				if w == id {
+					return nil, 0, fmt.Errorf("%w: %s cannot be colored", ErrSelfLoop, id)
+				}
+				adj[w] = true
+			}
+		}
+		adjacent[id] = adj
+	}
+
+	// order = vertices of G sorted by descending degree
+	order := make([]ID, 0, len(adjacent))
+	for id := range adjacent {
+		order = append(order, id)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		di, dj := len(adjacent[order[i]]), len(adjacent[order[j]])
+		if di != dj {
+			return di > dj
+		}
+		return lessID(order[i], order[j])
+	})
+
+	color := make(map[ID]int, len(order))
+
+	// c = 0
+	c := 0
+
+	// while some vertex is not colored:
+	for ; len(color) < len(order); c++ {
+
+		// for each vertex u in order:
+		for _, u := range order {
+			if _, ok := color[u]; ok {
+				continue
+			}
+
+			// no vertex adjacent to u has color c
+			free := true
+			for w := range adjacent[u] {
+				if cw, ok := color[w]; ok && cw == c {
+					free = false
+					break
+				}
+			}
+			if free {
+				// color[u] = c
+				color[u] = c
+			}
+		}
+	}
+
+	return color, c, nil
+}
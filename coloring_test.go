@@ -0,0 +1,110 @@
+package goraph
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// checkColoring fails if two adjacent nodes share a color.
+func checkColoring(t *testing.T, g Graph, color map[ID]int) {
+	for _, edge := range g.Edges() {
+		src, tgt := edge.Source().ID(), edge.Target().ID()
+		if color[src] == color[tgt] {
+			t.Fatalf("Expected different colors for %s and %s but %v", src, tgt, color)
+		}
+	}
+	if len(color) != g.NodeCount() {
+		t.Fatalf("Expected %d colored nodes but %v", g.NodeCount(), color)
+	}
+}
+
+func TestGreedyColoring(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	color, n, err := GreedyColoring(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkColoring(t, g, color)
+
+	maxDegree := 0
+	for id := range g.Nodes() {
+		adj := make(map[ID]bool)
+		cmap, _ := g.ChildNodesOf(id)
+		pmap, _ := g.ParentNodesOf(id)
+		for _, m := range []map[ID]Node{cmap, pmap} {
+			for w := range m {
+				adj[w] = true
+			}
+		}
+		if len(adj) > maxDegree {
+			maxDegree = len(adj)
+		}
+	}
+	if n > maxDegree+1 {
+		t.Fatalf("Expected at most %d colors but %d", maxDegree+1, n)
+	}
+}
+
+func TestGreedyColoring_wheel(t *testing.T) {
+	// a hub connected to a cycle of five nodes needs four colors:
+	// three for the odd cycle and one for the hub
+	g := NewGraph()
+	g.AddNode(NewNode("H", nil))
+	for i := 0; i < 5; i++ {
+		g.AddNode(NewNode(fmt.Sprintf("R%d", i), nil))
+	}
+	for i := 0; i < 5; i++ {
+		r := StringID(fmt.Sprintf("R%d", i))
+		g.AddEdge(r, StringID(fmt.Sprintf("R%d", (i+1)%5)), 1)
+		g.AddEdge(StringID("H"), r, 1)
+	}
+	color, n, err := GreedyColoring(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkColoring(t, g, color)
+	if n != 4 {
+		t.Fatalf("Expected 4 colors but %d: %v", n, color)
+	}
+	// the hub has the highest degree and is colored first
+	if color[StringID("H")] != 0 {
+		t.Fatalf("Expected color 0 for H but %v", color)
+	}
+
+	// a bipartite graph needs two colors
+	b := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D"} {
+		b.AddNode(NewNode(id, nil))
+	}
+	b.AddEdge(StringID("A"), StringID("B"), 1)
+	b.AddEdge(StringID("C"), StringID("B"), 1)
+	b.AddEdge(StringID("C"), StringID("D"), 1)
+	b.AddEdge(StringID("D"), StringID("A"), 1)
+	color, n, err = GreedyColoring(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkColoring(t, b, color)
+	if n != 2 {
+		t.Fatalf("Expected 2 colors but %d: %v", n, color)
+	}
+
+	if _, n, err := GreedyColoring(NewGraph()); err != nil || n != 0 {
+		t.Fatalf("Expected no colors for an empty graph but %d, %v", n, err)
+	}
+
+	b.AddEdge(StringID("A"), StringID("A"), 1)
+	if _, _, err := GreedyColoring(b); !errors.Is(err, ErrSelfLoop) {
+		t.Fatalf("Expected ErrSelfLoop but %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package goraph
+
+import "fmt"
+
+func (g *graph) ToColumns() (srcs []string, tgts []string, weights []float64) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	srcs, tgts, weights = []string{}, []string{}, []float64{}
+	for _, id1 := range sortedIDs(g.nodes) {
+		cmap := make(map[ID]Node, len(g.nodeChildren[id1]))
+		for id2 := range g.nodeChildren[id1] {
+			cmap[id2] = g.nodes[id2]
+		}
+		for _, id2 := range sortedIDs(cmap) {
+			srcs = append(srcs, id1.String())
+			tgts = append(tgts, id2.String())
+			weights = append(weights, g.nodeChildren[id1][id2])
+		}
+	}
+	return srcs, tgts, weights
+}
+
+// NewGraphFromColumns returns a new Graph from an edge list in columns,
+// as returned by ToColumns, where the k-th edge goes from srcs[k] to
+// tgts[k] with weights[k]. Nodes are created on first reference, and a
+// repeated edge replaces the earlier weight. It returns error if the
+// columns do not have the same length.
+func NewGraphFromColumns(graphID string, srcs, tgts []string, weights []float64, opts ...Option) (Graph, error) {
+	if len(srcs) != len(tgts) || len(srcs) != len(weights) {
+		return nil, fmt.Errorf("columns must have the same length but %d sources, %d targets and %d weights", len(srcs), len(tgts), len(weights))
+	}
+
+	g := newGraph(opts...)
+	g.id = graphID
+	for k := range srcs {
+		nd1, err := g.loadNode(srcs[k])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", k, err)
+		}
+		nd2, err := g.loadNode(tgts[k])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", k, err)
+		}
+		if err := g.ReplaceEdge(nd1.ID(), nd2.ID(), weights[k]); err != nil {
+			return nil, fmt.Errorf("row %d: %w", k, err)
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,53 @@
+package goraph
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestGraph_ToColumns(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g1, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srcs, tgts, weights := g1.ToColumns()
+	edges := g1.Edges()
+	if len(srcs) != len(edges) || len(tgts) != len(edges) || len(weights) != len(edges) {
+		t.Fatalf("Expected %d rows but %d %d %d", len(edges), len(srcs), len(tgts), len(weights))
+	}
+	for k, edge := range edges {
+		if srcs[k] != edge.Source().ID().String() || tgts[k] != edge.Target().ID().String() || weights[k] != edge.Weight() {
+			t.Fatalf("Expected %s at row %d but %s %s %f", edge, k, srcs[k], tgts[k], weights[k])
+		}
+	}
+
+	g2, err := NewGraphFromColumns("graph_00", srcs, tgts, weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(g1, g2) {
+		t.Fatalf("Expected %s but %s", g1, g2)
+	}
+	srcs2, tgts2, weights2 := g2.ToColumns()
+	if !reflect.DeepEqual(srcs, srcs2) || !reflect.DeepEqual(tgts, tgts2) || !reflect.DeepEqual(weights, weights2) {
+		t.Fatal("Expected the same columns after the round trip")
+	}
+
+	if _, err := NewGraphFromColumns("g", []string{"A", "B"}, []string{"B"}, []float64{1, 2}); err == nil {
+		t.Fatal("Expected error for columns of different lengths")
+	}
+	if _, err := NewGraphFromColumns("g", []string{"A"}, []string{"B"}, []float64{1, 2}); err == nil {
+		t.Fatal("Expected error for columns of different lengths")
+	}
+	g3, err := NewGraphFromColumns("g", nil, nil, nil)
+	if err != nil || g3.NodeCount() != 0 {
+		t.Fatalf("Expected an empty graph but %v %v", g3, err)
+	}
+}
@@ -0,0 +1,128 @@
+package goraph
+
+import "sort"
+
+// communityEpsilon is the smallest modularity gain for which
+// Communities moves a node to another community, so that
+// rounding errors cannot make it loop forever.
+const communityEpsilon = 1e-12
+
+// Communities detects communities by greedily optimizing modularity
+// with the first pass of the Louvain method. The graph is treated as
+// undirected, where the weight between two nodes is the sum of the
+// weights of the edges in both directions. Self-loops and edges with
+// negative weights are ignored. The modularity is
+//
+//	Q = 1/2m * Σ_ij (A_ij - k_i * k_j / 2m) * δ(c_i, c_j)
+//
+// where A_ij is the undirected weight between i and j, k_i is the sum
+// of the weights of i, 2m is the sum of all k_i, and δ(c_i, c_j) is 1
+// if i and j are in the same community. Nodes are visited in sorted
+// order, so the result is deterministic. Each community is sorted, and
+// the communities are sorted by their first IDs.
+// (https://en.wikipedia.org/wiki/Louvain_method)
+//
+//	 0. Communities(G)
+//	 1.
+//	 2. 	for each vertex v in G:
+//	 3. 		community[v] = v
+//	 4.
+//	 5. 	repeat until no vertex moves:
+//	 6. 		for each vertex v in G:
+//	 7. 			remove v from community[v]
+//	 8. 			C = the community of v or of a neighbor of v
+//	 9. 			    with the largest modularity gain
+//	10. 			community[v] = C
+//
+func Communities(g Graph) [][]ID {
+	ids := sortedIDs(g.Nodes())
+	index := make(map[ID]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	// adj is the undirected weight between nodes,
+	// and degree is the sum of the weights of a node.
+	adj := make([]map[int]float64, len(ids))
+	for i := range adj {
+		adj[i] = make(map[int]float64)
+	}
+	degree := make([]float64, len(ids))
+	m2 := 0.0
+	for _, edge := range g.Edges() {
+		i, j := index[edge.Source().ID()], index[edge.Target().ID()]
+		if i == j || edge.Weight() <= 0 {
+			continue
+		}
+		adj[i][j] += edge.Weight()
+		adj[j][i] += edge.Weight()
+		degree[i] += edge.Weight()
+		degree[j] += edge.Weight()
+		m2 += 2 * edge.Weight()
+	}
+
+	// for each vertex v in G:
+	//   community[v] = v
+	community := make([]int, len(ids))
+	total := make([]float64, len(ids))
+	for i := range ids {
+		community[i] = i
+		total[i] = degree[i]
+	}
+
+	// repeat until no vertex moves:
+	for moved := m2 > 0; moved; {
+		moved = false
+
+		// for each vertex v in G:
+		for i := range ids {
+			// weights from v to each neighboring community
+			weights := make(map[int]float64)
+			for j, w := range adj[i] {
+				weights[community[j]] += w
+			}
+			cs := make([]int, 0, len(weights))
+			for c := range weights {
+				cs = append(cs, c)
+			}
+			sort.Ints(cs)
+
+			// remove v from community[v]
+			current := community[i]
+			total[current] -= degree[i]
+
+			// C = the community of v or of a neighbor of v
+			//     with the largest modularity gain
+			best := current
+			bestGain := weights[current] - total[current]*degree[i]/m2
+			for _, c := range cs {
+				gain := weights[c] - total[c]*degree[i]/m2
+				if gain > bestGain+communityEpsilon {
+					best, bestGain = c, gain
+				}
+			}
+
+			// community[v] = C
+			total[best] += degree[i]
+			community[i] = best
+			if best != current {
+				moved = true
+			}
+		}
+	}
+
+	members := make(map[int][]ID)
+	order := []int{}
+	for i, id := range ids {
+		c := community[i]
+		if _, ok := members[c]; !ok {
+			order = append(order, c)
+		}
+		members[c] = append(members[c], id)
+	}
+	rs := make([][]ID, 0, len(order))
+	for _, c := range order {
+		rs = append(rs, members[c])
+	}
+	return rs
+}
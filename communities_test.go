@@ -0,0 +1,45 @@
+package goraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommunities(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D", "X", "Y", "Z", "W"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	for _, elem := range []struct {
+		id1, id2 string
+		weight   float64
+	}{
+		// two dense clusters
+		{"A", "B", 5}, {"B", "C", 5}, {"C", "A", 5}, {"D", "A", 5}, {"D", "B", 5},
+		{"X", "Y", 5}, {"Y", "Z", 5}, {"Z", "X", 5}, {"W", "X", 5}, {"Z", "W", 5},
+		// joined by a weak edge
+		{"C", "X", 1},
+	} {
+		g.AddEdge(StringID(elem.id1), StringID(elem.id2), elem.weight)
+	}
+
+	expected := [][]ID{
+		{StringID("A"), StringID("B"), StringID("C"), StringID("D")},
+		{StringID("W"), StringID("X"), StringID("Y"), StringID("Z")},
+	}
+	if rs := Communities(g); !reflect.DeepEqual(rs, expected) {
+		t.Fatalf("Expected %v but %v", expected, rs)
+	}
+
+	// nodes without edges stay alone
+	g = NewGraph()
+	g.AddNode(NewNode("A", nil))
+	g.AddNode(NewNode("B", nil))
+	expected = [][]ID{{StringID("A")}, {StringID("B")}}
+	if rs := Communities(g); !reflect.DeepEqual(rs, expected) {
+		t.Fatalf("Expected %v but %v", expected, rs)
+	}
+	if rs := Communities(NewGraph()); len(rs) != 0 {
+		t.Fatalf("Expected no communities but %v", rs)
+	}
+}
@@ -0,0 +1,80 @@
+package goraph
+
+// unionFind is a disjoint-set forest over node IDs with path
+// compression and union by size. Unknown IDs are added as singletons
+// when first seen.
+// (https://en.wikipedia.org/wiki/Disjoint-set_data_structure)
+type unionFind struct {
+	index  map[ID]int
+	parent []int
+	size   []int
+}
+
+func newUnionFind() *unionFind {
+	return &unionFind{index: make(map[ID]int)}
+}
+
+// find returns the root index of the set that contains id.
+func (uf *unionFind) find(id ID) int {
+	x, ok := uf.index[id]
+	if !ok {
+		x = len(uf.parent)
+		uf.index[id] = x
+		uf.parent = append(uf.parent, x)
+		uf.size = append(uf.size, 1)
+		return x
+	}
+	root := x
+	for uf.parent[root] != root {
+		root = uf.parent[root]
+	}
+	for uf.parent[x] != root {
+		uf.parent[x], x = root, uf.parent[x]
+	}
+	return root
+}
+
+// union merges the sets that contain id1 and id2.
+func (uf *unionFind) union(id1, id2 ID) {
+	r1, r2 := uf.find(id1), uf.find(id2)
+	if r1 == r2 {
+		return
+	}
+	if uf.size[r1] < uf.size[r2] {
+		r1, r2 = r2, r1
+	}
+	uf.parent[r2] = r1
+	uf.size[r1] += uf.size[r2]
+}
+
+// ComponentOf keeps a union-find of the weakly connected components,
+// which AddEdge and ReplaceEdge update in near-constant time. Deleting
+// or renaming a node, or deleting an edge, can split a component, so
+// it drops the union-find and the next query rebuilds it from all the
+// edges in O(|V| log |V| + |E|).
+func (g *graph) ComponentOf(id ID) (int, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeExistID(id) {
+		return 0, &NodeNotFoundError{ID: id}
+	}
+
+	// find compresses paths, so readers take turns
+	g.compMu.Lock()
+	defer g.compMu.Unlock()
+
+	if g.components == nil {
+		uf := newUnionFind()
+		for _, nid := range sortedIDs(g.nodes) {
+			uf.find(nid)
+		}
+		for src, cmap := range g.nodeChildren {
+			for tgt := range cmap {
+				uf.union(src, tgt)
+			}
+		}
+		g.components = uf
+	}
+	return g.components.find(id), nil
+}
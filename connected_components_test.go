@@ -0,0 +1,95 @@
+package goraph
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestGraph_ComponentOf(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	same := func(id1, id2 string) bool {
+		c1, err := g.ComponentOf(StringID(id1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c2, err := g.ComponentOf(StringID(id2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c1 == c2
+	}
+
+	if same("A", "B") {
+		t.Fatal("Expected A and B in different components")
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	if !same("A", "B") {
+		t.Fatal("Expected A and B in the same component after AddEdge")
+	}
+	// the direction of the edge does not matter
+	g.ReplaceEdge(StringID("D"), StringID("B"), 1)
+	if !same("A", "D") {
+		t.Fatal("Expected A and D in the same component after ReplaceEdge")
+	}
+	if same("A", "C") {
+		t.Fatal("Expected A and C in different components")
+	}
+
+	// nodes added after the union-find was built
+	g.AddNode(NewNode("E", nil))
+	if same("E", "C") {
+		t.Fatal("Expected E and C in different components")
+	}
+	g.AddEdge(StringID("C"), StringID("E"), 1)
+	if !same("E", "C") || same("E", "A") {
+		t.Fatal("Expected C and E in their own component")
+	}
+
+	// deletions split components
+	g.DeleteEdge(StringID("D"), StringID("B"))
+	if same("A", "D") || !same("A", "B") {
+		t.Fatal("Expected D to be split from A and B after DeleteEdge")
+	}
+	g.AddEdge(StringID("B"), StringID("C"), 1)
+	g.DeleteNode(StringID("C"))
+	if same("B", "E") {
+		t.Fatal("Expected B and E in different components after DeleteNode")
+	}
+	if err := g.RenameNode(StringID("B"), StringID("X")); err != nil {
+		t.Fatal(err)
+	}
+	if !same("A", "X") {
+		t.Fatal("Expected A and X in the same component after RenameNode")
+	}
+
+	if _, err := g.ComponentOf(StringID("B")); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+}
+
+func TestGraph_ComponentOf_IsConnected(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	comps := make(map[int]bool)
+	for id := range g.Nodes() {
+		c, err := g.ComponentOf(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		comps[c] = true
+	}
+	if len(comps) != 1 || !g.IsConnected() {
+		t.Fatalf("Expected one component but %d", len(comps))
+	}
+}
@@ -0,0 +1,32 @@
+package goraph
+
+import "sort"
+
+// COO returns the coordinate (COO) format of the weight matrix,
+// as used by sparse matrix libraries such as scipy.sparse.coo_matrix.
+// (https://en.wikipedia.org/wiki/Sparse_matrix#Coordinate_list_(COO))
+func (g *graph) COO() (rows []int, cols []int, data []float64, ids []ID) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ids = sortedIDs(g.nodes)
+	index := make(map[ID]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	rows, cols, data = []int{}, []int{}, []float64{}
+	for i, id1 := range ids {
+		row := []int{}
+		for id2 := range g.nodeChildren[id1] {
+			row = append(row, index[id2])
+		}
+		sort.Ints(row)
+		for _, j := range row {
+			rows = append(rows, i)
+			cols = append(cols, j)
+			data = append(data, g.nodeChildren[id1][ids[j]])
+		}
+	}
+	return rows, cols, data, ids
+}
@@ -0,0 +1,44 @@
+package goraph
+
+import (
+	"os"
+	"testing"
+)
+
+func TestGraph_COO(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, cols, data, ids := g.COO()
+	if len(ids) != g.NodeCount() {
+		t.Fatalf("Expected %d ids but %v", g.NodeCount(), ids)
+	}
+	for i := 1; i < len(ids); i++ {
+		if !lessID(ids[i-1], ids[i]) {
+			t.Fatalf("Expected sorted ids but %v", ids)
+		}
+	}
+
+	edges := g.Edges()
+	if len(rows) != len(edges) || len(cols) != len(edges) || len(data) != len(edges) {
+		t.Fatalf("Expected %d triples but %d %d %d", len(edges), len(rows), len(cols), len(data))
+	}
+	for k, edge := range edges {
+		src, tgt := ids[rows[k]], ids[cols[k]]
+		if src != edge.Source().ID() || tgt != edge.Target().ID() || data[k] != edge.Weight() {
+			t.Fatalf("Expected %s but %s -- %.3f -→ %s", edge, src, data[k], tgt)
+		}
+	}
+
+	rows, cols, data, ids = NewGraph().COO()
+	if len(rows) != 0 || len(cols) != 0 || len(data) != 0 || len(ids) != 0 {
+		t.Fatalf("Expected empty COO but %v %v %v %v", rows, cols, data, ids)
+	}
+}
@@ -0,0 +1,110 @@
+package goraph
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvHeader is the optional first row of a CSV edge list.
+var csvHeader = []string{"source", "target", "weight"}
+
+// NewGraphFromCSV returns a new Graph from a CSV edge list with
+// one edge per row. The weight column is optional and defaults to 1.
+// The first row is skipped if it is a "source,target" or
+// "source,target,weight" header. Nodes are created on first reference,
+// and a repeated edge replaces the earlier weight.
+// Here's the sample CSV data:
+//
+//	source,target,weight
+//	S,A,100
+//	S,B,14
+//	A,B
+//
+func NewGraphFromCSV(rd io.Reader, graphID string, opts ...Option) (Graph, error) {
+	r := csv.NewReader(rd)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	g := newGraph(opts...)
+	g.id = graphID
+	for first := true; ; first = false {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+
+		if first && isCSVHeader(record) {
+			continue
+		}
+		if len(record) != 2 && len(record) != 3 {
+			return nil, fmt.Errorf("line %d: expected 2 or 3 columns but %d", line, len(record))
+		}
+		weight := 1.0
+		if len(record) == 3 {
+			weight, err = strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid weight %q", line, record[2])
+			}
+		}
+
+		nd1, err := g.loadNode(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		nd2, err := g.loadNode(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := g.ReplaceEdge(nd1.ID(), nd2.ID(), weight); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	return g, nil
+}
+
+func isCSVHeader(record []string) bool {
+	if len(record) != 2 && len(record) != 3 {
+		return false
+	}
+	for i, v := range record {
+		if !strings.EqualFold(strings.TrimSpace(v), csvHeader[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ExportToCSV writes the edges of the graph to w as a CSV edge list
+// with a header, in the format read by NewGraphFromCSV. Rows are sorted
+// by source and target IDs. Nodes without any edge are not written.
+func ExportToCSV(g Graph, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, id1 := range sortedIDs(g.Nodes()) {
+		cmap, err := g.ChildNodesOf(id1)
+		if err != nil {
+			return err
+		}
+		for _, id2 := range sortedIDs(cmap) {
+			weight, err := g.EdgeWeight(id1, id2)
+			if err != nil {
+				return err
+			}
+			record := []string{id1.String(), id2.String(), strconv.FormatFloat(weight, 'g', -1, 64)}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,81 @@
+package goraph
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewGraphFromCSV(t *testing.T) {
+	data := `source,target,weight
+S,A,100
+S,B,14.5
+A,B
+`
+	g, err := NewGraphFromCSV(strings.NewReader(data), "graph_csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NodeCount() != 3 {
+		t.Fatalf("Expected 3 nodes but %s", g)
+	}
+	for _, elem := range []struct {
+		id1, id2 string
+		weight   float64
+	}{{"S", "A", 100}, {"S", "B", 14.5}, {"A", "B", 1}} {
+		if v, err := g.EdgeWeight(StringID(elem.id1), StringID(elem.id2)); err != nil || v != elem.weight {
+			t.Fatalf("weight from %s to %s must be %f but %v %v", elem.id1, elem.id2, elem.weight, v, err)
+		}
+	}
+
+	// without header
+	g, err = NewGraphFromCSV(strings.NewReader("S,A,100\n"), "graph_csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NodeCount() != 2 {
+		t.Fatalf("Expected 2 nodes but %s", g)
+	}
+
+	for data, line := range map[string]string{
+		"source,target\nS,A,x\n":  "line 2",
+		"S,A,1\nS,B,2\nS\n":       "line 3",
+		"S,A,1\nS,B,2,3\nA,B,1\n": "line 2",
+	} {
+		_, err := NewGraphFromCSV(strings.NewReader(data), "graph_csv")
+		if err == nil || !strings.Contains(err.Error(), line) {
+			t.Fatalf("Expected an error on %s but %v", line, err)
+		}
+	}
+}
+
+func TestExportToCSV(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g1, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := ExportToCSV(g1, buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "source,target,weight\nA,B,5\nA,D,20\n") {
+		t.Fatalf("Unexpected output\n%s", buf)
+	}
+	g2, err := NewGraphFromCSV(buf, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g2.NodeCount() != g1.NodeCount() {
+		t.Fatalf("Expected %d nodes but %d", g1.NodeCount(), g2.NodeCount())
+	}
+	if !reflect.DeepEqual(g1.(*graph).nodeChildren, g2.(*graph).nodeChildren) {
+		t.Fatalf("Expected %s but %s", g1, g2)
+	}
+}
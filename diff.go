@@ -0,0 +1,88 @@
+package goraph
+
+import (
+	"math"
+	"sort"
+)
+
+// GraphDiff describes the changes from one graph to another.
+// All slices are sorted by node IDs.
+type GraphDiff struct {
+	// AddedNodes and RemovedNodes are the IDs of the nodes
+	// only in the new and only in the old graph.
+	AddedNodes   []ID
+	RemovedNodes []ID
+
+	// AddedEdges and RemovedEdges are the edges only in the
+	// new and only in the old graph.
+	AddedEdges   []Edge
+	RemovedEdges []Edge
+
+	// ChangedEdges are the edges in both graphs whose weights
+	// differ by more than EqualEpsilon.
+	ChangedEdges []WeightChange
+}
+
+// WeightChange is the change of the weight of an edge.
+type WeightChange struct {
+	Source    ID
+	Target    ID
+	OldWeight float64
+	NewWeight float64
+}
+
+// Diff compares two graphs by node IDs and edge weights.
+// Node properties are not compared.
+func Diff(old, new Graph) GraphDiff {
+	d := GraphDiff{
+		AddedNodes:   []ID{},
+		RemovedNodes: []ID{},
+		AddedEdges:   []Edge{},
+		RemovedEdges: []Edge{},
+		ChangedEdges: []WeightChange{},
+	}
+
+	onodes, nnodes := old.Nodes(), new.Nodes()
+	for id := range nnodes {
+		if _, ok := onodes[id]; !ok {
+			d.AddedNodes = append(d.AddedNodes, id)
+		}
+	}
+	for id := range onodes {
+		if _, ok := nnodes[id]; !ok {
+			d.RemovedNodes = append(d.RemovedNodes, id)
+		}
+	}
+	sort.Sort(idSlice(d.AddedNodes))
+	sort.Sort(idSlice(d.RemovedNodes))
+
+	nedges := new.Edges()
+	nweights := make(map[[2]ID]float64)
+	for _, edge := range nedges {
+		nweights[[2]ID{edge.Source().ID(), edge.Target().ID()}] = edge.Weight()
+	}
+	for _, edge := range old.Edges() {
+		key := [2]ID{edge.Source().ID(), edge.Target().ID()}
+		weight, ok := nweights[key]
+		if !ok {
+			d.RemovedEdges = append(d.RemovedEdges, edge)
+			continue
+		}
+		if math.Abs(weight-edge.Weight()) > EqualEpsilon {
+			d.ChangedEdges = append(d.ChangedEdges, WeightChange{
+				Source:    key[0],
+				Target:    key[1],
+				OldWeight: edge.Weight(),
+				NewWeight: weight,
+			})
+		}
+		delete(nweights, key)
+	}
+	for _, edge := range nedges {
+		if _, ok := nweights[[2]ID{edge.Source().ID(), edge.Target().ID()}]; ok {
+			d.AddedEdges = append(d.AddedEdges, edge)
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,56 @@
+package goraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D"} {
+		old.AddNode(NewNode(id, nil))
+	}
+	old.AddEdge(StringID("A"), StringID("B"), 1)
+	old.AddEdge(StringID("B"), StringID("C"), 2)
+	old.AddEdge(StringID("C"), StringID("D"), 3)
+
+	new := NewGraph()
+	for _, id := range []string{"A", "B", "C", "E"} {
+		new.AddNode(NewNode(id, nil))
+	}
+	new.AddEdge(StringID("A"), StringID("B"), 1)
+	new.AddEdge(StringID("B"), StringID("C"), 5)
+	new.AddEdge(StringID("C"), StringID("E"), 3)
+	new.AddEdge(StringID("A"), StringID("C"), 4)
+
+	d := Diff(old, new)
+	if !reflect.DeepEqual(d.AddedNodes, []ID{StringID("E")}) {
+		t.Fatalf("Expected added nodes [E] but %v", d.AddedNodes)
+	}
+	if !reflect.DeepEqual(d.RemovedNodes, []ID{StringID("D")}) {
+		t.Fatalf("Expected removed nodes [D] but %v", d.RemovedNodes)
+	}
+	if s := edgeStrings(d.AddedEdges); !reflect.DeepEqual(s, []string{"A -- 4.000 -→ C\n", "C -- 3.000 -→ E\n"}) {
+		t.Fatalf("Expected added edges A->C and C->E but %v", s)
+	}
+	if s := edgeStrings(d.RemovedEdges); !reflect.DeepEqual(s, []string{"C -- 3.000 -→ D\n"}) {
+		t.Fatalf("Expected removed edge C->D but %v", s)
+	}
+	expected := []WeightChange{{Source: StringID("B"), Target: StringID("C"), OldWeight: 2, NewWeight: 5}}
+	if !reflect.DeepEqual(d.ChangedEdges, expected) {
+		t.Fatalf("Expected %v but %v", expected, d.ChangedEdges)
+	}
+
+	d = Diff(old, old)
+	if len(d.AddedNodes)+len(d.RemovedNodes)+len(d.AddedEdges)+len(d.RemovedEdges)+len(d.ChangedEdges) != 0 {
+		t.Fatalf("Expected no changes but %+v", d)
+	}
+}
+
+func edgeStrings(edges []Edge) []string {
+	rs := []string{}
+	for _, edge := range edges {
+		rs = append(rs, edge.String())
+	}
+	return rs
+}
@@ -0,0 +1,126 @@
+package goraph
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// errEmptyGraph is returned by the metrics that
+// are undefined for a graph without nodes.
+var errEmptyGraph = errors.New("graph has no nodes")
+
+// Eccentricity returns the greatest shortest-path distance from the
+// node to any other node, following the edge directions. It uses
+// Dijkstra from the node, and returns ErrDisconnected if some node
+// cannot be reached, and error for negative weights.
+// (https://en.wikipedia.org/wiki/Distance_(graph_theory))
+func (g *graph) Eccentricity(id ID) (float64, error) {
+	if _, err := g.Node(id); err != nil {
+		return 0, err
+	}
+	if err := checkNonNegative(g); err != nil {
+		return 0, err
+	}
+
+	distance := make(map[ID]float64)
+	minHeap := &nodeDistanceHeap{}
+	heap.Push(minHeap, nodeDistance{id: id, distance: 0})
+	for minHeap.Len() != 0 {
+		u := heap.Pop(minHeap).(nodeDistance)
+		if _, ok := distance[u.id]; ok {
+			continue
+		}
+		distance[u.id] = u.distance
+
+		cmap, err := g.ChildNodesOf(u.id)
+		if err != nil {
+			return 0, err
+		}
+		for v := range cmap {
+			if _, ok := distance[v]; ok {
+				continue
+			}
+			weight, err := g.EdgeWeight(u.id, v)
+			if err != nil {
+				return 0, err
+			}
+			heap.Push(minHeap, nodeDistance{id: v, distance: u.distance + weight})
+		}
+	}
+	if len(distance) != g.NodeCount() {
+		return 0, ErrDisconnected
+	}
+
+	rs := 0.0
+	for _, d := range distance {
+		rs = math.Max(rs, d)
+	}
+	return rs, nil
+}
+
+// Diameter returns the greatest eccentricity of the nodes, using
+// AllPairsShortestPaths. It returns ErrDisconnected if some node
+// cannot be reached from another.
+func (g *graph) Diameter() (float64, error) {
+	ecc, err := eccentricities(g)
+	if err != nil {
+		return 0, err
+	}
+	rs := 0.0
+	for _, e := range ecc {
+		rs = math.Max(rs, e)
+	}
+	return rs, nil
+}
+
+// Radius returns the smallest eccentricity of the nodes, using
+// AllPairsShortestPaths. It returns ErrDisconnected if some node
+// cannot be reached from another.
+func (g *graph) Radius() (float64, error) {
+	ecc, err := eccentricities(g)
+	if err != nil {
+		return 0, err
+	}
+	rs := math.Inf(1)
+	for _, e := range ecc {
+		rs = math.Min(rs, e)
+	}
+	return rs, nil
+}
+
+// eccentricities returns the eccentricity of every node.
+func eccentricities(g Graph) (map[ID]float64, error) {
+	if g.NodeCount() == 0 {
+		return nil, errEmptyGraph
+	}
+	if err := checkNonNegative(g); err != nil {
+		return nil, err
+	}
+	distance, err := AllPairsShortestPaths(g)
+	if err != nil {
+		return nil, err
+	}
+
+	rs := make(map[ID]float64)
+	for id, dmap := range distance {
+		for _, d := range dmap {
+			if math.IsInf(d, 1) {
+				return nil, ErrDisconnected
+			}
+			rs[id] = math.Max(rs[id], d)
+		}
+	}
+	return rs, nil
+}
+
+// checkNonNegative returns error if an edge has a negative weight.
+func checkNonNegative(g Graph) error {
+	for _, edge := range g.Edges() {
+		if edge.Weight() < 0 {
+			return fmt.Errorf("weight from %s to %s must not be negative but %f", edge.Source(), edge.Target(), edge.Weight())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package goraph
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGraph_Eccentricity(t *testing.T) {
+	// A <-> B <-> C <-> D, with a shortcut from A to D
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	for _, elem := range []struct {
+		id1, id2 string
+		weight   float64
+	}{
+		{"A", "B", 1}, {"B", "A", 1},
+		{"B", "C", 2}, {"C", "B", 2},
+		{"C", "D", 3}, {"D", "C", 3},
+		{"A", "D", 4},
+	} {
+		g.AddEdge(StringID(elem.id1), StringID(elem.id2), elem.weight)
+	}
+
+	// A: B 1, C 3, D 4
+	// B: A 1, C 2, D 5
+	// C: A 3, B 2, D 3
+	// D: A 6, B 5, C 3
+	for id, expected := range map[string]float64{"A": 4, "B": 5, "C": 3, "D": 6} {
+		e, err := g.Eccentricity(StringID(id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e != expected {
+			t.Fatalf("Expected eccentricity %f of %s but %f", expected, id, e)
+		}
+	}
+	if d, err := g.Diameter(); err != nil || d != 6 {
+		t.Fatalf("Expected diameter 6 but %f %v", d, err)
+	}
+	if r, err := g.Radius(); err != nil || r != 3 {
+		t.Fatalf("Expected radius 3 but %f %v", r, err)
+	}
+
+	g.AddNode(NewNode("E", nil))
+	g.AddEdge(StringID("D"), StringID("E"), 1)
+	if _, err := g.Diameter(); !errors.Is(err, ErrDisconnected) {
+		t.Fatalf("Expected ErrDisconnected but %v", err)
+	}
+	if _, err := g.Radius(); !errors.Is(err, ErrDisconnected) {
+		t.Fatalf("Expected ErrDisconnected but %v", err)
+	}
+	if e, err := g.Eccentricity(StringID("A")); err != nil || e != 5 {
+		t.Fatalf("Expected eccentricity 5 of A but %f %v", e, err)
+	}
+	if e, err := g.Eccentricity(StringID("E")); !errors.Is(err, ErrDisconnected) {
+		t.Fatalf("Expected ErrDisconnected but %f %v", e, err)
+	}
+}
@@ -0,0 +1,93 @@
+package goraph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// NewGraphFromEdgeList returns a new Graph from a plain text edge list
+// with one "source target weight" edge per line, separated by spaces or
+// tabs. The weight is optional and defaults to 1. Blank lines and lines
+// beginning with "#" are skipped. Nodes are created on first reference,
+// and a repeated edge replaces the earlier weight.
+// Here's the sample edge list:
+//
+//	# source target weight
+//	S A 100
+//	S B 14
+//	A B
+//
+func NewGraphFromEdgeList(rd io.Reader, graphID string, opts ...Option) (Graph, error) {
+	g := newGraph(opts...)
+	g.id = graphID
+
+	sc := bufio.NewScanner(rd)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 && len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected 2 or 3 fields but %d", line, len(fields))
+		}
+		weight := 1.0
+		if len(fields) == 3 {
+			var err error
+			weight, err = strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid weight %q", line, fields[2])
+			}
+		}
+
+		nd1, err := g.loadNode(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		nd2, err := g.loadNode(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := g.ReplaceEdge(nd1.ID(), nd2.ID(), weight); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// ExportToEdgeList writes the edges of the graph to w as a plain text
+// edge list, in the format read by NewGraphFromEdgeList. Lines are sorted
+// by source and target IDs. Nodes without any edge are not written.
+// It returns error if an ID is empty, contains white space, or starts
+// with "#", which would be read back as a comment.
+func ExportToEdgeList(g Graph, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, id1 := range sortedIDs(g.Nodes()) {
+		cmap, err := g.ChildNodesOf(id1)
+		if err != nil {
+			return err
+		}
+		for _, id2 := range sortedIDs(cmap) {
+			weight, err := g.EdgeWeight(id1, id2)
+			if err != nil {
+				return err
+			}
+			for _, id := range []ID{id1, id2} {
+				if s := id.String(); s == "" || strings.IndexFunc(s, unicode.IsSpace) >= 0 || strings.HasPrefix(s, "#") {
+					return fmt.Errorf("ID %q cannot be written to an edge list", s)
+				}
+			}
+			fmt.Fprintf(bw, "%s %s %s\n", id1, id2, strconv.FormatFloat(weight, 'g', -1, 64))
+		}
+	}
+	return bw.Flush()
+}
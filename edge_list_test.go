@@ -0,0 +1,99 @@
+package goraph
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewGraphFromEdgeList(t *testing.T) {
+	data := `# source target weight
+S A 100
+  # indented comment
+S	B 14.5
+
+A B
+`
+	g, err := NewGraphFromEdgeList(strings.NewReader(data), "graph_edge_list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NodeCount() != 3 {
+		t.Fatalf("Expected 3 nodes but %s", g)
+	}
+	if g.HasNode(StringID("#")) {
+		t.Fatalf("Expected comments to be skipped but %s", g)
+	}
+	for _, elem := range []struct {
+		id1, id2 string
+		weight   float64
+	}{{"S", "A", 100}, {"S", "B", 14.5}, {"A", "B", 1}} {
+		if v, err := g.EdgeWeight(StringID(elem.id1), StringID(elem.id2)); err != nil || v != elem.weight {
+			t.Fatalf("weight from %s to %s must be %f but %v %v", elem.id1, elem.id2, elem.weight, v, err)
+		}
+	}
+
+	for data, line := range map[string]string{
+		"# comment\nS A x\n": "line 2",
+		"S A 1\nS B 2\nS\n":  "line 3",
+		"S A 1\nS B 2 3\n":   "line 2",
+	} {
+		_, err := NewGraphFromEdgeList(strings.NewReader(data), "graph_edge_list")
+		if err == nil || !strings.Contains(err.Error(), line) {
+			t.Fatalf("Expected an error on %s but %v", line, err)
+		}
+	}
+}
+
+func TestExportToEdgeList(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g1, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := ExportToEdgeList(g1, buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "A B 5\nA D 20\n") {
+		t.Fatalf("Unexpected output\n%s", buf)
+	}
+	g2, err := NewGraphFromEdgeList(buf, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(g1, g2) {
+		t.Fatalf("Expected %s but %s", g1, g2)
+	}
+
+	g := NewGraph()
+	g.AddNode(NewNode("A B", nil))
+	g.AddNode(NewNode("C", nil))
+	g.AddEdge(StringID("A B"), StringID("C"), 1)
+	if err := ExportToEdgeList(g, new(bytes.Buffer)); err == nil {
+		t.Fatal("Expected error for an ID with a space")
+	}
+
+	for _, id := range []string{"A\u00a0B", "A\vB"} {
+		g = NewGraph()
+		g.AddNode(NewNode(id, nil))
+		g.AddNode(NewNode("C", nil))
+		g.AddEdge(StringID(id), StringID("C"), 1)
+		if err := ExportToEdgeList(g, new(bytes.Buffer)); err == nil {
+			t.Fatalf("Expected error for the ID %q", id)
+		}
+	}
+
+	g = NewGraph()
+	g.AddNode(NewNode("#a", nil))
+	g.AddNode(NewNode("b", nil))
+	g.AddEdge(StringID("#a"), StringID("b"), 1)
+	if err := ExportToEdgeList(g, new(bytes.Buffer)); err == nil {
+		t.Fatal("Expected error for an ID starting with #")
+	}
+}
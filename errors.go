@@ -0,0 +1,93 @@
+package goraph
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNodeNotFound is matched by errors.Is when a node
+	// does not exist in the graph.
+	ErrNodeNotFound = errors.New("node does not exist in the graph")
+
+	// ErrEdgeNotFound is matched by errors.Is when an edge
+	// does not exist in the graph.
+	ErrEdgeNotFound = errors.New("edge does not exist in the graph")
+
+	// ErrSelfLoop is returned when adding an edge from a node to
+	// itself in a graph created with NewGraphNoSelfLoops.
+	ErrSelfLoop = errors.New("self-loops are not allowed in the graph")
+
+	// ErrDisconnected is returned when an algorithm requires
+	// a connected graph but some nodes cannot be reached.
+	ErrDisconnected = errors.New("graph is not connected")
+
+	// ErrNoPath is returned when the target cannot be
+	// reached from the source.
+	ErrNoPath = errors.New("there is no path between the nodes")
+
+	// ErrNegativeCycle is returned when the graph has a cycle
+	// whose total weight is negative, so that shortest paths
+	// through it are undefined.
+	ErrNegativeCycle = errors.New("there is a negative-weight cycle")
+
+	// ErrCyclic is returned when an algorithm requires
+	// a directed acyclic graph but the graph has a cycle.
+	ErrCyclic = errors.New("graph has a cycle")
+
+	// ErrNotBipartite is returned when the nodes cannot be split
+	// into two sets without an edge inside either set.
+	ErrNotBipartite = errors.New("graph is not bipartite")
+
+	// ErrStale is returned by the queries of an SPTree
+	// after Invalidate has been called.
+	ErrStale = errors.New("shortest path tree is stale")
+
+	// ErrDuplicateEdge is returned by NewGraphFromJSONStrict when
+	// the same edge is defined more than once.
+	ErrDuplicateEdge = errors.New("duplicate edge")
+
+	// ErrCapacityExceeded is returned when adding a node or an edge
+	// to a graph that has reached the limit set by WithMaxNodes
+	// or WithMaxEdges.
+	ErrCapacityExceeded = errors.New("graph capacity exceeded")
+
+	// ErrFrozen is returned when modifying a graph returned by Freeze.
+	ErrFrozen = errors.New("graph is frozen")
+
+	// ErrReservedID is returned when exporting or loading a node
+	// whose ID is a reserved key of the JSON or YAML format,
+	// such as "_props" or "_weights".
+	ErrReservedID = errors.New("node ID is a reserved key")
+)
+
+// NodeNotFoundError is returned when a node does not exist in the graph.
+// It matches ErrNodeNotFound with errors.Is.
+type NodeNotFoundError struct {
+	ID ID
+}
+
+func (e *NodeNotFoundError) Error() string {
+	return fmt.Sprintf("%s does not exist in the graph", e.ID)
+}
+
+// Is reports whether target is ErrNodeNotFound.
+func (e *NodeNotFoundError) Is(target error) bool {
+	return target == ErrNodeNotFound
+}
+
+// EdgeNotFoundError is returned when there is no edge from
+// Source to Target. It matches ErrEdgeNotFound with errors.Is.
+type EdgeNotFoundError struct {
+	Source ID
+	Target ID
+}
+
+func (e *EdgeNotFoundError) Error() string {
+	return fmt.Sprintf("there is no edge from %s to %s", e.Source, e.Target)
+}
+
+// Is reports whether target is ErrEdgeNotFound.
+func (e *EdgeNotFoundError) Is(target error) bool {
+	return target == ErrEdgeNotFound
+}
@@ -0,0 +1,66 @@
+package goraph
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, x := StringID("A"), StringID("X")
+
+	_, err1 := g.Node(x)
+	err2 := g.AddEdge(a, x, 1)
+	err3 := g.DeleteEdge(x, a)
+	_, err4 := g.EdgeWeight(x, a)
+	_, err5 := g.ParentNodesOf(x)
+	_, err6 := g.ChildNodesOf(x)
+	for i, err := range []error{err1, err2, err3, err4, err5, err6} {
+		if !errors.Is(err, ErrNodeNotFound) {
+			t.Fatalf("%d | Expected ErrNodeNotFound but %v", i, err)
+		}
+		if errors.Is(err, ErrEdgeNotFound) {
+			t.Fatalf("%d | Expected not ErrEdgeNotFound but %v", i, err)
+		}
+		var nerr *NodeNotFoundError
+		if !errors.As(err, &nerr) || nerr.ID != x {
+			t.Fatalf("%d | Expected NodeNotFoundError for X but %v", i, err)
+		}
+		if err.Error() != "X does not exist in the graph" {
+			t.Fatalf("%d | Unexpected message %q", i, err)
+		}
+	}
+
+	_, err = g.EdgeWeight(a, StringID("B"))
+	if !errors.Is(err, ErrEdgeNotFound) {
+		t.Fatalf("Expected ErrEdgeNotFound but %v", err)
+	}
+	var eerr *EdgeNotFoundError
+	if !errors.As(err, &eerr) || eerr.Source != a || eerr.Target != StringID("B") {
+		t.Fatalf("Expected EdgeNotFoundError from A to B but %v", err)
+	}
+	if err.Error() != "there is no edge from A to B" {
+		t.Fatalf("Unexpected message %q", err)
+	}
+
+	err = g.DeleteEdge(a, StringID("B"))
+	if !errors.Is(err, ErrEdgeNotFound) || errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrEdgeNotFound but %v", err)
+	}
+	if !errors.As(err, &eerr) || eerr.Source != a || eerr.Target != StringID("B") {
+		t.Fatalf("Expected EdgeNotFoundError from A to B but %v", err)
+	}
+
+	if _, err := g.Subgraph([]ID{a, x}); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package goraph
+
+// EventType is the kind of mutation described by a GraphEvent.
+type EventType int
+
+const (
+	// NodeAdded is fired when a node is added.
+	NodeAdded EventType = iota
+
+	// NodeDeleted is fired when a node is deleted, together
+	// with the edges from and to it.
+	NodeDeleted
+
+	// NodeRenamed is fired when a node is renamed from OldID to ID.
+	NodeRenamed
+
+	// EdgeAdded is fired when AddEdge adds an edge, where Weight
+	// is the weight after merging with an existing edge.
+	EdgeAdded
+
+	// EdgeReplaced is fired when the weight of an edge is set,
+	// by ReplaceEdge or MapWeights.
+	EdgeReplaced
+
+	// EdgeDeleted is fired when an edge is deleted.
+	EdgeDeleted
+)
+
+func (t EventType) String() string {
+	switch t {
+	case NodeAdded:
+		return "NodeAdded"
+	case NodeDeleted:
+		return "NodeDeleted"
+	case NodeRenamed:
+		return "NodeRenamed"
+	case EdgeAdded:
+		return "EdgeAdded"
+	case EdgeReplaced:
+		return "EdgeReplaced"
+	case EdgeDeleted:
+		return "EdgeDeleted"
+	}
+	return "EventType(unknown)"
+}
+
+// GraphEvent describes a successful mutation of a graph.
+// ID and OldID are set for node events, and Source, Target
+// and Weight for edge events.
+type GraphEvent struct {
+	Type   EventType
+	ID     ID
+	OldID  ID
+	Source ID
+	Target ID
+	Weight float64
+}
+
+func (g *graph) Subscribe(fn func(ev GraphEvent)) {
+	g.subMu.Lock()
+	defer g.subMu.Unlock()
+
+	g.subscribers = append(g.subscribers, fn)
+}
+
+// publish calls the subscribers with each event in order. Mutators
+// defer it before taking the lock, so that it runs after the lock
+// is released and a subscriber can call back into the graph.
+func (g *graph) publish(evs *[]GraphEvent) {
+	if len(*evs) == 0 {
+		return
+	}
+
+	g.subMu.Lock()
+	subscribers := g.subscribers
+	g.subMu.Unlock()
+
+	for _, ev := range *evs {
+		for _, fn := range subscribers {
+			fn(ev)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package goraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraph_Subscribe(t *testing.T) {
+	g := NewGraph()
+	evs := []GraphEvent{}
+	g.Subscribe(func(ev GraphEvent) {
+		// calling back into the graph must not deadlock
+		g.NodeCount()
+		evs = append(evs, ev)
+	})
+
+	g.AddNode(NewNode("A", nil))
+	g.AddNode(NewNode("A", nil)) // already exists
+	g.AddNodesFromIDs([]string{"B", "C"})
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("A"), StringID("B"), 2)
+	g.AddEdge(StringID("A"), StringID("X"), 1) // fails
+	g.ReplaceEdge(StringID("B"), StringID("C"), 5)
+	g.DeleteEdge(StringID("A"), StringID("B"))
+	g.DeleteEdge(StringID("A"), StringID("B")) // already deleted
+	g.RenameNode(StringID("C"), StringID("D"))
+	g.DeleteNode(StringID("A"))
+
+	expected := []GraphEvent{
+		{Type: NodeAdded, ID: StringID("A")},
+		{Type: NodeAdded, ID: StringID("B")},
+		{Type: NodeAdded, ID: StringID("C")},
+		{Type: EdgeAdded, Source: StringID("A"), Target: StringID("B"), Weight: 1},
+		{Type: EdgeAdded, Source: StringID("A"), Target: StringID("B"), Weight: 3},
+		{Type: EdgeReplaced, Source: StringID("B"), Target: StringID("C"), Weight: 5},
+		{Type: EdgeDeleted, Source: StringID("A"), Target: StringID("B")},
+		{Type: NodeRenamed, ID: StringID("D"), OldID: StringID("C")},
+		{Type: NodeDeleted, ID: StringID("A")},
+	}
+	if !reflect.DeepEqual(evs, expected) {
+		t.Fatalf("Expected %v but %v", expected, evs)
+	}
+
+	// a subscriber that mutates the graph sees its own events
+	evs = evs[:0]
+	g.Subscribe(func(ev GraphEvent) {
+		if ev.Type == NodeAdded && ev.ID == StringID("E") {
+			g.AddEdge(StringID("E"), StringID("D"), 1)
+		}
+	})
+	g.AddNode(NewNode("E", nil))
+	expected = []GraphEvent{
+		{Type: NodeAdded, ID: StringID("E")},
+		{Type: EdgeAdded, Source: StringID("E"), Target: StringID("D"), Weight: 1},
+	}
+	if !reflect.DeepEqual(evs, expected) {
+		t.Fatalf("Expected %v but %v", expected, evs)
+	}
+}
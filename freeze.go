@@ -0,0 +1,76 @@
+package goraph
+
+// frozenGraph is a read-only view of a Graph. Read methods are
+// promoted from the embedded Graph, and mutation methods fail.
+type frozenGraph struct {
+	Graph
+}
+
+// Freeze returns a read-only view of g. Mutation methods return
+// ErrFrozen, or false or 0 when they do not return error, and leave
+// g unchanged. Reset, SetEdgeMergeFunc and MapWeights do nothing.
+// The view is shallow: g can still be modified directly, and the
+// view sees the changes. Nodes are shared with g, so their
+// properties can still be changed with PropNode.SetProp.
+func Freeze(g Graph) Graph {
+	if _, ok := g.(*frozenGraph); ok {
+		return g
+	}
+	return &frozenGraph{Graph: g}
+}
+
+func (g *frozenGraph) Init() {}
+
+func (g *frozenGraph) Reset() {}
+
+func (g *frozenGraph) AddNode(nd Node) bool {
+	return false
+}
+
+func (g *frozenGraph) AddNodesFromIDs(ids []string) int {
+	return 0
+}
+
+func (g *frozenGraph) DeleteNode(id ID) bool {
+	return false
+}
+
+func (g *frozenGraph) DeleteNodes(ids []ID) int {
+	return 0
+}
+
+func (g *frozenGraph) RenameNode(oldID, newID ID) error {
+	return ErrFrozen
+}
+
+func (g *frozenGraph) AddEdge(id1, id2 ID, weight float64) error {
+	return ErrFrozen
+}
+
+func (g *frozenGraph) AddEdges(edges []Edge) []error {
+	errs := make([]error, len(edges))
+	for i := range errs {
+		errs[i] = ErrFrozen
+	}
+	return errs
+}
+
+func (g *frozenGraph) SetEdgeMergeFunc(fn func(existing, incoming float64) float64) {}
+
+func (g *frozenGraph) ReplaceEdge(id1, id2 ID, weight float64) error {
+	return ErrFrozen
+}
+
+func (g *frozenGraph) DeleteEdge(id1, id2 ID) error {
+	return ErrFrozen
+}
+
+func (g *frozenGraph) DeleteEdgesWhere(pred func(src, tgt ID, weight float64) bool) int {
+	return 0
+}
+
+func (g *frozenGraph) MapWeights(fn func(src, tgt ID, w float64) float64) {}
+
+func (g *frozenGraph) GobDecode(data []byte) error {
+	return ErrFrozen
+}
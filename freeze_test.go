@@ -0,0 +1,73 @@
+package goraph
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := g.String()
+	fg := Freeze(g)
+	if Freeze(fg) != fg {
+		t.Fatal("Expected freezing a frozen graph to return it")
+	}
+
+	// reads
+	if fg.NodeCount() != g.NodeCount() || fg.String() != before {
+		t.Fatalf("Expected %s but %s", before, fg)
+	}
+	if v, err := fg.EdgeWeight(StringID("S"), StringID("A")); err != nil || v != 100 {
+		t.Fatalf("Expected weight 100 but %v %v", v, err)
+	}
+	if path, _, err := Dijkstra(fg, StringID("S"), StringID("T")); err != nil || len(path) == 0 {
+		t.Fatalf("Expected a path but %v %v", path, err)
+	}
+
+	// writes
+	if fg.AddNode(NewNode("X", nil)) || fg.AddNodesFromIDs([]string{"X"}) != 0 {
+		t.Fatal("Expected adding nodes to fail")
+	}
+	if fg.DeleteNode(StringID("S")) || fg.DeleteNodes([]ID{StringID("S")}) != 0 {
+		t.Fatal("Expected deleting nodes to fail")
+	}
+	if fg.DeleteEdgesWhere(func(src, tgt ID, weight float64) bool { return true }) != 0 {
+		t.Fatal("Expected deleting edges to fail")
+	}
+	for _, err := range []error{
+		fg.RenameNode(StringID("S"), StringID("X")),
+		fg.AddEdge(StringID("S"), StringID("A"), 1),
+		fg.ReplaceEdge(StringID("S"), StringID("A"), 1),
+		fg.DeleteEdge(StringID("S"), StringID("A")),
+		fg.GobDecode(nil),
+	} {
+		if !errors.Is(err, ErrFrozen) {
+			t.Fatalf("Expected ErrFrozen but %v", err)
+		}
+	}
+	edges := []Edge{NewEdge(NewNode("S", nil), NewNode("A", nil), 1, nil)}
+	if errs := fg.AddEdges(edges); !reflect.DeepEqual(errs, []error{ErrFrozen}) {
+		t.Fatalf("Expected [ErrFrozen] but %v", errs)
+	}
+	fg.MapWeights(func(src, tgt ID, w float64) float64 { return 0 })
+	fg.Reset()
+	if g.String() != before {
+		t.Fatalf("Expected the graph to be unchanged but %s", g)
+	}
+
+	// the owner can still modify the graph
+	g.DeleteNode(StringID("S"))
+	if fg.HasNode(StringID("S")) {
+		t.Fatal("Expected the view to see the deletion")
+	}
+}
@@ -0,0 +1,113 @@
+package goraph
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"sort"
+)
+
+func init() {
+	// node IDs are encoded as interface values
+	gob.Register(StringID(""))
+	gob.Register(IntID(0))
+}
+
+// gobGraph is the gob representation of a graph, since
+// the fields of graph are unexported. Node IDs of types
+// other than StringID and IntID must be registered with
+// gob.Register.
+type gobGraph struct {
+	ID          string
+	Nodes       []gobNode
+	Edges       []gobEdge
+	NoSelfLoops bool
+}
+
+type gobNode struct {
+	ID         ID
+	Props      map[string]string
+	TypedProps map[string]interface{}
+	Weight     float64
+}
+
+type gobEdge struct {
+	Source ID
+	Target ID
+	Weight float64
+}
+
+func (g *graph) GobEncode() ([]byte, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	gg := gobGraph{ID: g.id, NoSelfLoops: g.noSelfLoops}
+	for _, id := range sortedIDs(g.nodes) {
+		nd := g.nodes[id]
+		gn := gobNode{ID: id, Props: nd.Props(), Weight: nodeWeight(nd)}
+		if n, ok := nd.(*node); ok && n.hasTypedProps() {
+			gn.TypedProps = n.TypedProps()
+		}
+		gg.Nodes = append(gg.Nodes, gn)
+	}
+	for _, id1 := range sortedIDs(g.nodes) {
+		ids := []ID{}
+		for id2 := range g.nodeChildren[id1] {
+			ids = append(ids, id2)
+		}
+		sort.Sort(idSlice(ids))
+		for _, id2 := range ids {
+			gg.Edges = append(gg.Edges, gobEdge{Source: id1, Target: id2, Weight: g.nodeChildren[id1][id2]})
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(gg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (g *graph) GobDecode(data []byte) error {
+	var gg gobGraph
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&gg); err != nil {
+		return err
+	}
+
+	// keep the capacity of the receiver
+	g.mu.RLock()
+	ng := newGraph(WithMaxNodes(g.maxNodes), WithMaxEdges(g.maxEdges))
+	g.mu.RUnlock()
+
+	ng.id = gg.ID
+	ng.noSelfLoops = gg.NoSelfLoops
+	for _, gn := range gg.Nodes {
+		var nd *node
+		if gn.TypedProps != nil {
+			nd = newTypedNode(gn.ID, gn.TypedProps)
+		} else {
+			nd = NewNodeWithID(gn.ID, gn.Props).(*node)
+		}
+		nd.weight = gn.Weight
+		if !ng.AddNode(nd) && !ng.HasNode(gn.ID) {
+			return fmt.Errorf("%w: at most %d nodes", ErrCapacityExceeded, ng.maxNodes)
+		}
+	}
+	for _, ge := range gg.Edges {
+		if err := ng.ReplaceEdge(ge.Source, ge.Target, ge.Weight); err != nil {
+			return err
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.id = ng.id
+	g.noSelfLoops = ng.noSelfLoops
+	g.nodes = ng.nodes
+	g.nodeParents = ng.nodeParents
+	g.nodeChildren = ng.nodeChildren
+	g.edgeCount = ng.edgeCount
+	g.components = nil
+	return nil
+}
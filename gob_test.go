@@ -0,0 +1,49 @@
+package goraph
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"testing"
+)
+
+func TestGraph_GobEncode(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.AddNode(NewNode("lonely", map[string]string{"color": "red"}))
+	g.AddNode(NewTypedNode("typed", map[string]interface{}{"count": 3, "ok": true}))
+	nd, _ := g.Node(StringID("S"))
+	nd.(PropNode).SetProp("kind", "source")
+
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(g); err != nil {
+		t.Fatal(err)
+	}
+	g2 := NewGraph()
+	if err := gob.NewDecoder(buf).Decode(g2); err != nil {
+		t.Fatal(err)
+	}
+	if g2.ID() != g.ID() {
+		t.Fatalf("Expected graph ID %s but %s", g.ID(), g2.ID())
+	}
+	if !Equal(g, g2) {
+		t.Fatalf("Expected equal graphs but\n%s\n%s", g, g2)
+	}
+	nd2, err := g2.Node(StringID("typed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := nd2.(TypedNode).TypedProps()["count"].(int); !ok || v != 3 {
+		t.Fatalf("Expected int 3 but %#v", nd2.(TypedNode).TypedProps()["count"])
+	}
+	if err := g2.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,10 +1,16 @@
 package goraph
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	yaml "gopkg.in/yaml.v2"
@@ -23,6 +29,58 @@ func (s StringID) String() string {
 	return string(s)
 }
 
+// IntID is a numeric ID, which is ordered by its value.
+type IntID int64
+
+func (i IntID) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
+// lessID orders IntIDs by their value,
+// and other IDs by their string value.
+func lessID(a, b ID) bool {
+	if ai, ok := a.(IntID); ok {
+		if bi, ok := b.(IntID); ok {
+			return ai < bi
+		}
+	}
+	return a.String() < b.String()
+}
+
+// sortedKeys returns the keys of the map in increasing order, so that
+// the loaders process a decoded document in a deterministic order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// idSlice is a slice of IDs sorted with lessID.
+type idSlice []ID
+
+func (s idSlice) Len() int {
+	return len(s)
+}
+func (s idSlice) Less(i, j int) bool {
+	return lessID(s[i], s[j])
+}
+func (s idSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// sortedIDs returns the keys of the node map sorted with lessID.
+func sortedIDs(nodes map[ID]Node) []ID {
+	ids := make([]ID, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Sort(idSlice(ids))
+	return ids
+}
+
 // Node represents a vertex. The ID must be unique within the graph.
 type Node interface {
 	// ID returns the node's ID.
@@ -31,35 +89,219 @@ type Node interface {
 	// String returns the string representation of the node which is the node ID.
 	String() string
 
-	// Props returns properties associated to a node
+	// Props returns a copy of the properties associated to a node.
 	Props() map[string]string
 }
 
-// Node is an internal type that implements the Node interface.
+// PropNode is a Node whose properties can be read and set one
+// at a time. Nodes created by this package are PropNodes.
+type PropNode interface {
+	Node
+
+	// Prop returns the property with the key, and
+	// false if the node does not have it.
+	Prop(key string) (string, bool)
+
+	// SetProp sets the property with the key.
+	SetProp(key, val string)
+}
+
+// TypedNode is a Node whose properties keep their original types.
+// Props returns the same properties formatted as strings.
+type TypedNode interface {
+	Node
+
+	// TypedProps returns a copy of the properties with their
+	// original types.
+	TypedProps() map[string]interface{}
+}
+
+// WeightedNode is a Node with a weight, such as the prize or
+// the cost of visiting the node. Nodes created by this package
+// are WeightedNodes, with weight 0 unless set otherwise.
+type WeightedNode interface {
+	Node
+
+	// Weight returns the weight of the node.
+	Weight() float64
+}
+
+// Node is an internal type that implements the Node, PropNode,
+// TypedNode and WeightedNode interfaces.
 type node struct {
-	id    string
-	props map[string]string
+	id ID
+
+	mu     sync.RWMutex // guards the following
+	props  map[string]string
+	weight float64
+
+	// typed holds the properties with their original types,
+	// and is nil for nodes that only have string properties.
+	typed map[string]interface{}
 }
 
 func (n *node) ID() ID {
-	return StringID(n.id)
+	return n.id
 }
 
 func (n *node) String() string {
-	return n.id
+	return n.id.String()
 }
 
 func (n *node) Props() map[string]string {
-	return n.props
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	rs := make(map[string]string, len(n.props))
+	for k, v := range n.props {
+		rs[k] = v
+	}
+	return rs
+}
+
+func (n *node) Prop(key string) (string, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	v, ok := n.props[key]
+	return v, ok
+}
+
+func (n *node) SetProp(key, val string) {
+	n.setTypedProp(key, val)
+}
+
+func (n *node) TypedProps() map[string]interface{} {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	rs := make(map[string]interface{})
+	if n.typed != nil {
+		for k, v := range n.typed {
+			rs[k] = v
+		}
+		return rs
+	}
+	for k, v := range n.props {
+		rs[k] = v
+	}
+	return rs
+}
+
+func (n *node) Weight() float64 {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.weight
+}
+
+func (n *node) setWeight(w float64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.weight = w
+}
+
+func (n *node) hasTypedProps() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.typed != nil
 }
 
-// NewNode creates a new Node type
+// setTypedProp sets the property, switching the node to typed
+// properties when v is not a string.
+func (n *node) setTypedProp(k string, v interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.props == nil {
+		n.props = make(map[string]string)
+	}
+	if s, ok := v.(string); ok && n.typed == nil {
+		n.props[k] = s
+		return
+	}
+	if n.typed == nil {
+		n.typed = make(map[string]interface{})
+		for k2, v2 := range n.props {
+			n.typed[k2] = v2
+		}
+	}
+	n.typed[k] = v
+	n.props[k] = fmt.Sprint(v)
+}
+
+// NewNode creates a new Node type. The properties are copied.
 func NewNode(id string, props map[string]string) Node {
+	return NewNodeWithID(StringID(id), props)
+}
+
+// NewNodeWithID creates a new Node type with any ID, such as
+// an IntID. The properties are copied.
+func NewNodeWithID(id ID, props map[string]string) Node {
 	// TODO : Check if id is unique in the graph
-	return &node{
+	nd := &node{
 		id:    id,
-		props: props,
+		props: make(map[string]string, len(props)),
+	}
+	for k, v := range props {
+		nd.props[k] = v
+	}
+	return nd
+}
+
+// NewTypedNode creates a new TypedNode whose properties can
+// be of any type, such as numbers or booleans.
+func NewTypedNode(id string, props map[string]interface{}) TypedNode {
+	return newTypedNode(StringID(id), props)
+}
+
+func newTypedNode(id ID, props map[string]interface{}) *node {
+	nd := &node{
+		id:    id,
+		props: make(map[string]string),
+		typed: make(map[string]interface{}),
+	}
+	for k, v := range props {
+		nd.setTypedProp(k, v)
+	}
+	return nd
+}
+
+// NewWeightedNode creates a new WeightedNode with the weight.
+// The properties are copied.
+func NewWeightedNode(id string, weight float64, props map[string]string) WeightedNode {
+	nd := NewNodeWithID(StringID(id), props).(*node)
+	nd.weight = weight
+	return nd
+}
+
+// nodeWeight returns the weight of a WeightedNode, and 0 otherwise.
+func nodeWeight(nd Node) float64 {
+	if wn, ok := nd.(WeightedNode); ok {
+		return wn.Weight()
+	}
+	return 0
+}
+
+// copyNode returns a copy of the node that does not share
+// its properties with the original.
+func copyNode(nd Node) Node {
+	return copyNodeAs(nd, nd.ID())
+}
+
+// copyNodeAs returns a copy of the node with the id, keeping
+// typed properties if the node has them, and its weight.
+func copyNodeAs(nd Node, id ID) Node {
+	var cp *node
+	if n, ok := nd.(*node); ok && n.hasTypedProps() {
+		cp = newTypedNode(id, n.TypedProps())
+	} else {
+		cp = NewNodeWithID(id, nd.Props()).(*node)
 	}
+	cp.weight = nodeWeight(nd)
+	return cp
 }
 
 var nodeCnt uint64
@@ -124,26 +366,45 @@ func NewUnweightedEdge(src, tgt Node, props map[string]string) Edge {
 	}
 }
 
-// EdgeSlice is a slice of Edge types
+// EdgeSlice is a slice of Edge types, sorted by weight.
+// Edges with the same weight are sorted by source and target IDs.
 type EdgeSlice []Edge
 
 func (e EdgeSlice) Len() int {
 	return len(e)
 }
 func (e EdgeSlice) Less(i, j int) bool {
-	return e[i].Weight() < e[j].Weight()
+	if e[i].Weight() != e[j].Weight() {
+		return e[i].Weight() < e[j].Weight()
+	}
+	src1, src2 := e[i].Source().ID(), e[j].Source().ID()
+	if src1 != src2 {
+		return lessID(src1, src2)
+	}
+	return lessID(e[i].Target().ID(), e[j].Target().ID())
 }
 func (e EdgeSlice) Swap(i, j int) {
 	e[i], e[j] = e[j], e[i]
 }
 
+// WeightFunc returns the weight used for an edge from src to tgt
+// in place of the stored weight, such as one scaled by a property.
+type WeightFunc func(src, tgt Node, stored float64) float64
+
 // Graph describes the methods of graph operations.
 // It assumes that the identifier of a Node is unique.
 // And weight values is float64.
 type Graph interface {
-	// Init initializes a Graph.
+	// Init initializes a Graph that has not been initialized yet.
+	// It does nothing on a graph that has been, so it never discards
+	// nodes or edges. Use Reset to clear a graph.
 	Init()
 
+	// Reset deletes all nodes and edges, keeping the graph ID and
+	// the options the graph was created with. Subscribers receive
+	// NodeDeleted for each node.
+	Reset()
+
 	// ID returns the node's ID.
 	ID() ID
 
@@ -153,33 +414,126 @@ type Graph interface {
 	// Node finds the Node.
 	Node(id ID) (Node, error)
 
-	// Nodes returns a map from node ID to
-	// empty struct value. Graph does not allow duplicate
-	// node ID or name.
+	// HasNode returns true if the node exists in the graph.
+	HasNode(id ID) bool
+
+	// Nodes returns a copy of the map from node ID to
+	// Node. Graph does not allow duplicate node ID or name.
 	Nodes() map[ID]Node
 
+	// NodesSorted returns the Nodes ordered by the
+	// string value of their IDs.
+	NodesSorted() []Node
+
+	// EachNode calls fn for each node while holding the read lock,
+	// until fn returns false. fn must not modify the graph.
+	EachNode(fn func(Node) bool)
+
+	// FindNodesByProp returns the nodes whose property key
+	// equals value, sorted by ID.
+	FindNodesByProp(key, value string) []Node
+
+	// Snapshot returns an immutable copy of the nodes and edges
+	// that can be read without locking the graph.
+	Snapshot() GraphSnapshot
+
 	// AddNode adds a node to a graph, and returns false
-	// if the node already existed in the graph.
+	// if the node already existed in the graph, or if the
+	// graph already has the number of nodes set by WithMaxNodes.
 	AddNode(nd Node) bool
 
+	// AddNodesFromIDs adds a node without properties for each ID
+	// that does not exist yet, and returns the number of nodes added.
+	// It stops adding once the graph reaches its node capacity.
+	AddNodesFromIDs(ids []string) int
+
 	// DeleteNode deletes a node from a graph.
 	// It returns true if it got deleted.
 	// And false if it didn't get deleted.
 	DeleteNode(id ID) bool
 
+	// DeleteNodes deletes the nodes and all of their edges.
+	// It returns the number of nodes that got deleted.
+	DeleteNodes(ids []ID) int
+
+	// RenameNode changes the ID of a node, keeping all of its edges.
+	// It returns error if oldID does not exist or newID already exists.
+	RenameNode(oldID, newID ID) error
+
 	// AddEdge adds an edge from nd1 to nd2 with the weight.
-	// It returns error if a node does not exist.
+	// It returns error if a node does not exist. If the edge
+	// already exists, the weights are combined as set by
+	// SetEdgeMergeFunc.
 	AddEdge(id1, id2 ID, weight float64) error
 
+	// AddEdges adds all edges while holding the lock once.
+	// It returns one error per edge, nil if that edge got added.
+	AddEdges(edges []Edge) []error
+
+	// SetEdgeMergeFunc sets the function that AddEdge uses to combine
+	// the existing and the incoming weight of an edge that already
+	// exists. The default, restored by passing nil, sums the weights.
+	SetEdgeMergeFunc(fn func(existing, incoming float64) float64)
+
 	// ReplaceEdge replaces an edge from id1 to id2 with the weight.
 	ReplaceEdge(id1, id2 ID, weight float64) error
 
-	// DeleteEdge deletes an edge from id1 to id2.
+	// DeleteEdge deletes an edge from id1 to id2. It returns
+	// error matching ErrEdgeNotFound if there is no such edge.
 	DeleteEdge(id1, id2 ID) error
 
+	// DeleteEdgesWhere deletes every edge for which pred returns
+	// true, and returns the number of deleted edges.
+	// pred must not call any method of the graph.
+	DeleteEdgesWhere(pred func(src, tgt ID, weight float64) bool) int
+
+	// HasEdge returns true if there is an edge from id1 to id2.
+	HasEdge(id1, id2 ID) bool
+
+	// MapWeights replaces the weight of every edge with the value
+	// returned by fn. fn must not modify the graph.
+	MapWeights(fn func(src, tgt ID, w float64) float64)
+
+	// ComponentOf returns the number of the weakly connected
+	// component of the node. Two nodes are connected if and only if
+	// they have the same number, which may change when the graph
+	// is modified. It is fastest when edges are only added.
+	ComponentOf(id ID) (int, error)
+
 	// EdgeWeight returns the weight from id1 to id2.
 	EdgeWeight(id1, id2 ID) (float64, error)
 
+	// PathWeight returns the sum of the edge weights between
+	// consecutive nodes of the path, which is 0 for a single node.
+	PathWeight(path []ID) (float64, error)
+
+	// ReverseWeight returns the weight from id1 to id2 as stored
+	// with the parents of id2. It equals EdgeWeight unless the
+	// graph is corrupted, see Validate.
+	ReverseWeight(id1, id2 ID) (float64, error)
+
+	// EffectiveWeight returns the weight from id1 to id2 transformed
+	// by fn, which is given the endpoint nodes and the stored weight.
+	// The graph is not modified.
+	EffectiveWeight(id1, id2 ID, fn WeightFunc) (float64, error)
+
+	// ClosestNodes returns up to n nodes nearest to from by
+	// shortest-path distance, with their distances.
+	ClosestNodes(from ID, n int) ([]ID, []float64, error)
+
+	// DegreeAssortativity returns the correlation of the
+	// degrees of the nodes joined by the edges.
+	DegreeAssortativity() (float64, error)
+
+	// ApproxMemoryBytes returns a rough estimate of the heap
+	// memory used by the nodes, edges and properties.
+	ApproxMemoryBytes() int
+
+	// WeightStats returns the minimum, maximum, mean and population
+	// standard deviation of the edge weights, and the number of edges.
+	// All values are zero for a graph without edges.
+	WeightStats() (min, max, mean, stddev float64, count int)
+
 	// ParentNodesOf returns the map of parent Nodes.
 	// (Nodes that come towards the argument vertex.)
 	ParentNodesOf(id ID) (map[ID]Node, error)
@@ -188,12 +542,167 @@ type Graph interface {
 	// (Nodes that go out of the argument vertex.)
 	ChildNodesOf(id ID) (map[ID]Node, error)
 
+	// ParentNodesSorted returns the parent Nodes ordered
+	// by the string value of their IDs.
+	ParentNodesSorted(id ID) ([]Node, error)
+
+	// ChildNodesSorted returns the child Nodes ordered
+	// by the string value of their IDs.
+	ChildNodesSorted(id ID) ([]Node, error)
+
+	// ParentNodesFiltered returns the parent Nodes for which pred
+	// returns true, given the weight of the edge to id.
+	// pred must not call any method of the graph.
+	ParentNodesFiltered(id ID, pred func(n Node, weight float64) bool) (map[ID]Node, error)
+
+	// ChildNodesFiltered returns the child Nodes for which pred
+	// returns true, given the weight of the edge from id.
+	// pred must not call any method of the graph.
+	ChildNodesFiltered(id ID, pred func(n Node, weight float64) bool) (map[ID]Node, error)
+
+	// TopChildren returns the n outgoing edges with the highest
+	// weights, sorted by descending weight and then by target ID.
+	TopChildren(id ID, n int) ([]Edge, error)
+
 	// ExportToJSON serializes the graph into a JSON file and
-	// saves to disk.
+	// saves to disk. It returns the edge weights and the "_weights"
+	// section decoded from the written file, without "_props" whose
+	// values are not numbers. It returns nil if the graph could not
+	// be serialized or the file could not be written, so use the
+	// ExportToJSON function to get the error.
 	ExportToJSON(path string) map[string]map[string]map[string]float64
 
-	// String describes the Graph.
+	// StreamJSON writes the graph to w in the same format and with
+	// the same bytes as the ExportToJSON function, one node at a time
+	// instead of building the whole document in memory.
+	StreamJSON(w io.Writer) error
+
+	// Edges returns all edges sorted by source and target IDs.
+	Edges() []Edge
+
+	// EachEdge calls fn for each edge while holding the read lock,
+	// until fn returns false. The order is unspecified.
+	// fn must not modify the graph.
+	EachEdge(fn func(src, tgt ID, weight float64) bool)
+
+	// COO returns the weight matrix in coordinate format, where the
+	// edge k goes from ids[rows[k]] to ids[cols[k]] with weight
+	// data[k]. ids is sorted, and the triples are sorted by row
+	// and column.
+	COO() (rows []int, cols []int, data []float64, ids []ID)
+
+	// ToColumns returns the edges as three columns of the same length,
+	// where the k-th edge goes from srcs[k] to tgts[k] with weights[k],
+	// sorted by source and target IDs. Nodes without edges are left out.
+	ToColumns() (srcs []string, tgts []string, weights []float64)
+
+	// LaplacianMatrix returns the combinatorial Laplacian D - A, or the
+	// symmetric normalized Laplacian if normalized is true, of the graph
+	// treated as undirected. ids is sorted and gives the row order.
+	LaplacianMatrix(normalized bool) ([][]float64, []ID, error)
+
+	// MarshalProto serializes the graph ID, nodes with properties and
+	// weights, and edges with weights into the protocol buffer format
+	// of goraph.proto. Typed properties keep their types.
+	MarshalProto() ([]byte, error)
+
+	// GobEncode serializes the graph ID, nodes with properties,
+	// and edges with weights, implementing gob.GobEncoder.
+	GobEncode() ([]byte, error)
+
+	// GobDecode replaces the graph with one serialized by
+	// GobEncode, implementing gob.GobDecoder.
+	GobDecode(data []byte) error
+
+	// Subscribe registers fn to be called with an event after each
+	// successful mutation of the nodes or edges, in the order of the
+	// mutations. fn is called after the graph lock is released, so it
+	// may call back into the graph. Events are not fired by Init,
+	// GobDecode or PropNode.SetProp.
+	Subscribe(fn func(ev GraphEvent))
+
+	// String describes the Graph with one line per edge,
+	// sorted by source and target IDs.
 	String() string
+
+	// SelfLoops returns the sorted IDs of the nodes
+	// that have an edge to themselves.
+	SelfLoops() []ID
+
+	// Sources returns the sorted IDs of the nodes
+	// without incoming edges.
+	Sources() []ID
+
+	// Sinks returns the sorted IDs of the nodes
+	// without outgoing edges.
+	Sinks() []ID
+
+	// Reverse returns a new graph with the same nodes and every edge
+	// flipped in direction. Node properties are copied.
+	Reverse() Graph
+
+	// IsConnected returns true if the graph is weakly connected,
+	// that is connected when edges are treated as undirected.
+	// An empty graph is considered connected.
+	IsConnected() bool
+
+	// IsStronglyConnected returns true if every node is reachable
+	// from every other node following the edge directions.
+	// An empty graph is considered strongly connected.
+	IsStronglyConnected() bool
+
+	// IsBipartite returns true and a 2-coloring of the nodes if
+	// the graph is bipartite when edges are treated as undirected.
+	IsBipartite() (bool, map[ID]int, error)
+
+	// CentralityReport returns the in and out degrees of every node,
+	// both as edge counts and as sums of the edge weights.
+	CentralityReport() map[ID]Centrality
+
+	// Eccentricity returns the greatest shortest-path
+	// distance from the node to any other node.
+	Eccentricity(id ID) (float64, error)
+
+	// Diameter returns the greatest eccentricity of the nodes.
+	Diameter() (float64, error)
+
+	// Radius returns the smallest eccentricity of the nodes.
+	Radius() (float64, error)
+
+	// Neighborhood returns the nodes reachable within k outgoing
+	// hops from the node. The node itself is only included if
+	// it can be reached back through a cycle.
+	Neighborhood(id ID, k int) (map[ID]Node, error)
+
+	// ArticulationPoints returns the sorted IDs of the nodes that
+	// disconnect the graph, treated as undirected, when removed.
+	ArticulationPoints() []ID
+
+	// Bridges returns the sorted edges that disconnect the
+	// graph, treated as undirected, when removed.
+	Bridges() []Edge
+
+	// UnreachableFrom returns the sorted IDs of the nodes that
+	// cannot be reached from root following outgoing edges.
+	UnreachableFrom(root ID) ([]ID, error)
+
+	// CanReach returns true if to can be reached from from following
+	// outgoing edges. A node can always reach itself.
+	CanReach(from, to ID) (bool, error)
+
+	// Subgraph returns a new graph with only the given nodes and
+	// the edges between them. Node properties are copied.
+	// It returns error listing the IDs that do not exist.
+	Subgraph(ids []ID) (Graph, error)
+
+	// Threshold returns a new graph with all the nodes and only the
+	// edges whose weight is at least min. Node properties are copied.
+	Threshold(min float64) Graph
+
+	// Validate checks that the parent and child maps mirror each
+	// other and only reference existing nodes. It returns an error
+	// listing every inconsistency found.
+	Validate() error
 }
 
 // graph is an internal default graph type that
@@ -214,6 +723,38 @@ type graph struct {
 	// nodeChildren maps a Node identifer to targets(children)
 	// with edge weights.
 	nodeChildren map[ID]map[ID]float64
+
+	// noSelfLoops rejects edges from a node to itself.
+	noSelfLoops bool
+
+	// mergeEdge returns the weight when AddEdge adds an edge
+	// that already exists. nil sums the weights.
+	mergeEdge func(existing, incoming float64) float64
+
+	// intIDs makes the loaders parse node IDs as IntID.
+	intIDs bool
+
+	// validateID checks the node IDs read by the loaders.
+	validateID func(id string) error
+
+	// maxNodes and maxEdges cap the size of the graph,
+	// where 0 means no limit.
+	maxNodes int
+	maxEdges int
+
+	// edgeCount is the number of edges.
+	edgeCount int
+
+	// components is the union-find used by ComponentOf, or nil
+	// after a deletion. Mutators update it under mu, and readers
+	// under the read lock of mu and compMu.
+	components *unionFind
+	compMu     sync.Mutex
+
+	subMu sync.Mutex // guards subscribers
+
+	// subscribers are called after each mutation.
+	subscribers []func(GraphEvent)
 }
 
 func (g *graph) Init() {
@@ -224,9 +765,33 @@ func (g *graph) Init() {
 	// (X) *g = *newGraph()
 	// assignment copies lock value
 
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.nodes != nil {
+		return
+	}
+	g.unsafeInit()
+}
+
+func (g *graph) Reset() {
+	var evs []GraphEvent
+	defer g.publish(&evs)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, id := range sortedIDs(g.nodes) {
+		evs = append(evs, GraphEvent{Type: NodeDeleted, ID: id})
+	}
+	g.unsafeInit()
+}
+
+func (g *graph) unsafeInit() {
 	g.nodes = make(map[ID]Node)
 	g.nodeParents = make(map[ID]map[ID]float64)
 	g.nodeChildren = make(map[ID]map[ID]float64)
+	g.edgeCount = 0
+	g.components = nil
 }
 
 func (g *graph) NodeCount() int {
@@ -248,17 +813,90 @@ func (g *graph) Node(id ID) (Node, error) {
 	defer g.mu.RUnlock()
 
 	if !g.unsafeExistID(id) {
-		return nil, fmt.Errorf("%s does not exist in the graph", id)
+		return nil, &NodeNotFoundError{ID: id}
 	}
 
 	return g.nodes[id], nil
 }
 
+func (g *graph) HasNode(id ID) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.unsafeExistID(id)
+}
+
 func (g *graph) Nodes() map[ID]Node {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	return g.nodes
+	rs := make(map[ID]Node, len(g.nodes))
+	for id, nd := range g.nodes {
+		rs[id] = nd
+	}
+	return rs
+}
+
+func (g *graph) NodesSorted() []Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	rs := make([]Node, 0, len(g.nodes))
+	for _, nd := range g.nodes {
+		rs = append(rs, nd)
+	}
+	sortNodesByString(rs)
+	return rs
+}
+
+// sortNodesByString sorts the nodes by the string value of their
+// IDs. IDs of different types with the same string are ordered by
+// type name, so that the order does not depend on map iteration.
+func sortNodesByString(nds []Node) {
+	sort.Slice(nds, func(i, j int) bool {
+		a, b := nds[i].ID(), nds[j].ID()
+		if a.String() != b.String() {
+			return a.String() < b.String()
+		}
+		return fmt.Sprintf("%T", a) < fmt.Sprintf("%T", b)
+	})
+}
+
+// nodeProp returns the property of the node with the key,
+// without copying all the properties of a PropNode.
+func nodeProp(nd Node, key string) (string, bool) {
+	if pn, ok := nd.(PropNode); ok {
+		return pn.Prop(key)
+	}
+	v, ok := nd.Props()[key]
+	return v, ok
+}
+
+func (g *graph) FindNodesByProp(key, value string) []Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// scan rather than index, since SetProp can change
+	// the properties of a node already in the graph
+	rs := []Node{}
+	for _, nd := range g.nodes {
+		if v, ok := nodeProp(nd, key); ok && v == value {
+			rs = append(rs, nd)
+		}
+	}
+	sort.Slice(rs, func(i, j int) bool { return lessID(rs[i].ID(), rs[j].ID()) })
+	return rs
+}
+
+func (g *graph) EachNode(fn func(Node) bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for _, nd := range g.nodes {
+		if !fn(nd) {
+			return
+		}
+	}
 }
 
 func (g *graph) unsafeExistID(id ID) bool {
@@ -267,19 +905,46 @@ func (g *graph) unsafeExistID(id ID) bool {
 }
 
 func (g *graph) AddNode(nd Node) bool {
+	var evs []GraphEvent
+	defer g.publish(&evs)
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	if g.unsafeExistID(nd.ID()) {
+	if g.unsafeExistID(nd.ID()) || g.unsafeNodesFull() {
 		return false
 	}
 
 	id := nd.ID()
 	g.nodes[id] = nd
+	evs = append(evs, GraphEvent{Type: NodeAdded, ID: id})
 	return true
 }
 
+func (g *graph) AddNodesFromIDs(ids []string) int {
+	var evs []GraphEvent
+	defer g.publish(&evs)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	cnt := 0
+	for _, id := range ids {
+		nd := NewNode(id, nil)
+		if g.unsafeExistID(nd.ID()) {
+			continue
+		}
+		if g.unsafeNodesFull() {
+			break
+		}
+		g.nodes[nd.ID()] = nd
+		evs = append(evs, GraphEvent{Type: NodeAdded, ID: nd.ID()})
+		cnt++
+	}
+	return cnt
+}
+
 func (g *graph) DeleteNode(id ID) bool {
+	var evs []GraphEvent
+	defer g.publish(&evs)
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
@@ -287,7 +952,12 @@ func (g *graph) DeleteNode(id ID) bool {
 		return false
 	}
 
+	g.edgeCount -= len(g.nodeChildren[id]) + len(g.nodeParents[id])
+	if _, ok := g.nodeChildren[id][id]; ok {
+		g.edgeCount++ // counted twice
+	}
 	delete(g.nodes, id)
+	g.components = nil
 
 	delete(g.nodeChildren, id)
 	for _, smap := range g.nodeChildren {
@@ -299,55 +969,156 @@ func (g *graph) DeleteNode(id ID) bool {
 		delete(smap, id)
 	}
 
+	evs = append(evs, GraphEvent{Type: NodeDeleted, ID: id})
 	return true
 }
 
-func (g *graph) AddEdge(id1, id2 ID, weight float64) error {
+func (g *graph) DeleteNodes(ids []ID) int {
+	var evs []GraphEvent
+	defer g.publish(&evs)
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	if !g.unsafeExistID(id1) {
-		return fmt.Errorf("%s does not exist in the graph", id1)
+	deleted := make(map[ID]struct{})
+	for _, id := range ids {
+		if g.unsafeExistID(id) {
+			deleted[id] = struct{}{}
+		}
 	}
-	if !g.unsafeExistID(id2) {
-		return fmt.Errorf("%s does not exist in the graph", id2)
+	if len(deleted) == 0 {
+		return 0
+	}
+	g.components = nil
+
+	// an edge between two deleted nodes is only counted
+	// as a child edge
+	for id := range deleted {
+		g.edgeCount -= len(g.nodeChildren[id])
+		for pid := range g.nodeParents[id] {
+			if _, ok := deleted[pid]; !ok {
+				g.edgeCount--
+			}
+		}
+	}
+	for _, id := range ids {
+		if !g.unsafeExistID(id) {
+			continue
+		}
+		delete(g.nodes, id)
+		delete(g.nodeChildren, id)
+		delete(g.nodeParents, id)
+		evs = append(evs, GraphEvent{Type: NodeDeleted, ID: id})
 	}
 
-	if _, ok := g.nodeChildren[id1]; ok {
-		if v, ok2 := g.nodeChildren[id1][id2]; ok2 {
-			g.nodeChildren[id1][id2] = v + weight
-		} else {
-			g.nodeChildren[id1][id2] = weight
+	for _, smap := range g.nodeChildren {
+		for id := range deleted {
+			delete(smap, id)
 		}
-	} else {
-		tmap := make(map[ID]float64)
-		tmap[id2] = weight
-		g.nodeChildren[id1] = tmap
 	}
-	if _, ok := g.nodeParents[id2]; ok {
-		if v, ok2 := g.nodeParents[id2][id1]; ok2 {
-			g.nodeParents[id2][id1] = v + weight
-		} else {
-			g.nodeParents[id2][id1] = weight
+	for _, smap := range g.nodeParents {
+		for id := range deleted {
+			delete(smap, id)
 		}
-	} else {
+	}
+
+	return len(deleted)
+}
+
+func (g *graph) RenameNode(oldID, newID ID) error {
+	var evs []GraphEvent
+	defer g.publish(&evs)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.unsafeExistID(oldID) {
+		return &NodeNotFoundError{ID: oldID}
+	}
+	if g.unsafeExistID(newID) {
+		return fmt.Errorf("%s already exists in the graph", newID)
+	}
+
+	nd := g.nodes[oldID]
+	delete(g.nodes, oldID)
+	g.nodes[newID] = copyNodeAs(nd, newID)
+	g.components = nil
+
+	rename := func(id ID) ID {
+		if id == oldID {
+			return newID
+		}
+		return id
+	}
+
+	if cmap, ok := g.nodeChildren[oldID]; ok {
+		delete(g.nodeChildren, oldID)
 		tmap := make(map[ID]float64)
-		tmap[id1] = weight
-		g.nodeParents[id2] = tmap
+		for id, weight := range cmap {
+			tmap[rename(id)] = weight
+			if id != oldID {
+				delete(g.nodeParents[id], oldID)
+				g.nodeParents[id][newID] = weight
+			}
+		}
+		g.nodeChildren[newID] = tmap
+	}
+	if pmap, ok := g.nodeParents[oldID]; ok {
+		delete(g.nodeParents, oldID)
+		tmap := make(map[ID]float64)
+		for id, weight := range pmap {
+			tmap[rename(id)] = weight
+			if id != oldID {
+				delete(g.nodeChildren[id], oldID)
+				g.nodeChildren[id][newID] = weight
+			}
+		}
+		g.nodeParents[newID] = tmap
 	}
 
+	evs = append(evs, GraphEvent{Type: NodeRenamed, ID: newID, OldID: oldID})
 	return nil
 }
 
-func (g *graph) ReplaceEdge(id1, id2 ID, weight float64) error {
+func (g *graph) AddEdge(id1, id2 ID, weight float64) error {
+	var evs []GraphEvent
+	defer g.publish(&evs)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.unsafeAddEdge(id1, id2, weight, &evs)
+}
+
+func (g *graph) AddEdges(edges []Edge) []error {
+	var evs []GraphEvent
+	defer g.publish(&evs)
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	errs := make([]error, len(edges))
+	for i, edge := range edges {
+		errs[i] = g.unsafeAddEdge(edge.Source().ID(), edge.Target().ID(), edge.Weight(), &evs)
+	}
+	return errs
+}
+
+func (g *graph) unsafeAddEdge(id1, id2 ID, weight float64, evs *[]GraphEvent) error {
 	if !g.unsafeExistID(id1) {
-		return fmt.Errorf("%s does not exist in the graph", id1)
+		return &NodeNotFoundError{ID: id1}
 	}
 	if !g.unsafeExistID(id2) {
-		return fmt.Errorf("%s does not exist in the graph", id2)
+		return &NodeNotFoundError{ID: id2}
+	}
+	if g.noSelfLoops && id1 == id2 {
+		return ErrSelfLoop
+	}
+
+	if v, ok := g.nodeChildren[id1][id2]; ok {
+		if g.mergeEdge != nil {
+			weight = g.mergeEdge(v, weight)
+		} else {
+			weight = v + weight
+		}
+	} else if err := g.unsafeAddEdgeCount(); err != nil {
+		return err
 	}
 
 	if _, ok := g.nodeChildren[id1]; ok {
@@ -364,209 +1135,1480 @@ func (g *graph) ReplaceEdge(id1, id2 ID, weight float64) error {
 		tmap[id1] = weight
 		g.nodeParents[id2] = tmap
 	}
-	return nil
+
+	if g.components != nil {
+		g.components.union(id1, id2)
+	}
+	if evs != nil {
+		*evs = append(*evs, GraphEvent{Type: EdgeAdded, Source: id1, Target: id2, Weight: weight})
+	}
+	return nil
+}
+
+// nodesFull returns true if the graph cannot take another node.
+func (g *graph) nodesFull() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.unsafeNodesFull()
+}
+
+// unsafeNodesFull returns true if the graph cannot take another node.
+func (g *graph) unsafeNodesFull() bool {
+	return g.maxNodes > 0 && len(g.nodes) >= g.maxNodes
+}
+
+// unsafeAddEdgeCount counts a new edge, or returns
+// ErrCapacityExceeded if the graph cannot take another edge.
+func (g *graph) unsafeAddEdgeCount() error {
+	if g.maxEdges > 0 && g.edgeCount >= g.maxEdges {
+		return fmt.Errorf("%w: at most %d edges", ErrCapacityExceeded, g.maxEdges)
+	}
+	g.edgeCount++
+	return nil
+}
+
+func (g *graph) SetEdgeMergeFunc(fn func(existing, incoming float64) float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.mergeEdge = fn
+}
+
+func (g *graph) ReplaceEdge(id1, id2 ID, weight float64) error {
+	var evs []GraphEvent
+	defer g.publish(&evs)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.unsafeExistID(id1) {
+		return &NodeNotFoundError{ID: id1}
+	}
+	if !g.unsafeExistID(id2) {
+		return &NodeNotFoundError{ID: id2}
+	}
+	if g.noSelfLoops && id1 == id2 {
+		return ErrSelfLoop
+	}
+	if _, ok := g.nodeChildren[id1][id2]; !ok {
+		if err := g.unsafeAddEdgeCount(); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := g.nodeChildren[id1]; ok {
+		g.nodeChildren[id1][id2] = weight
+	} else {
+		tmap := make(map[ID]float64)
+		tmap[id2] = weight
+		g.nodeChildren[id1] = tmap
+	}
+	if _, ok := g.nodeParents[id2]; ok {
+		g.nodeParents[id2][id1] = weight
+	} else {
+		tmap := make(map[ID]float64)
+		tmap[id1] = weight
+		g.nodeParents[id2] = tmap
+	}
+	if g.components != nil {
+		g.components.union(id1, id2)
+	}
+	evs = append(evs, GraphEvent{Type: EdgeReplaced, Source: id1, Target: id2, Weight: weight})
+	return nil
 }
 
 func (g *graph) DeleteEdge(id1, id2 ID) error {
+	var evs []GraphEvent
+	defer g.publish(&evs)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.unsafeExistID(id1) {
+		return &NodeNotFoundError{ID: id1}
+	}
+	if !g.unsafeExistID(id2) {
+		return &NodeNotFoundError{ID: id2}
+	}
+
+	if _, ok := g.nodeChildren[id1][id2]; !ok {
+		return &EdgeNotFoundError{Source: id1, Target: id2}
+	}
+	delete(g.nodeChildren[id1], id2)
+	delete(g.nodeParents[id2], id1)
+	g.edgeCount--
+	g.components = nil
+	evs = append(evs, GraphEvent{Type: EdgeDeleted, Source: id1, Target: id2})
+	return nil
+}
+
+func (g *graph) HasEdge(id1, id2 ID) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	_, ok := g.nodeChildren[id1][id2]
+	return ok
+}
+
+func (g *graph) DeleteEdgesWhere(pred func(src, tgt ID, weight float64) bool) int {
+	var evs []GraphEvent
+	defer g.publish(&evs)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	cnt := 0
+	for id1, tmap := range g.nodeChildren {
+		for id2, weight := range tmap {
+			if !pred(id1, id2, weight) {
+				continue
+			}
+			delete(tmap, id2)
+			delete(g.nodeParents[id2], id1)
+			cnt++
+			evs = append(evs, GraphEvent{Type: EdgeDeleted, Source: id1, Target: id2})
+		}
+	}
+	if cnt > 0 {
+		g.edgeCount -= cnt
+		g.components = nil
+	}
+	return cnt
+}
+
+func (g *graph) MapWeights(fn func(src, tgt ID, w float64) float64) {
+	var evs []GraphEvent
+	defer g.publish(&evs)
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	if !g.unsafeExistID(id1) {
-		return fmt.Errorf("%s does not exist in the graph", id1)
+	for id1, tmap := range g.nodeChildren {
+		for id2, weight := range tmap {
+			weight = fn(id1, id2, weight)
+			tmap[id2] = weight
+			g.nodeParents[id2][id1] = weight
+			evs = append(evs, GraphEvent{Type: EdgeReplaced, Source: id1, Target: id2, Weight: weight})
+		}
+	}
+}
+
+func (g *graph) EffectiveWeight(id1, id2 ID, fn WeightFunc) (float64, error) {
+	g.mu.RLock()
+	nd1, ok1 := g.nodes[id1]
+	nd2, ok2 := g.nodes[id2]
+	weight, ok := g.nodeChildren[id1][id2]
+	g.mu.RUnlock()
+
+	switch {
+	case !ok1:
+		return 0, &NodeNotFoundError{ID: id1}
+	case !ok2:
+		return 0, &NodeNotFoundError{ID: id2}
+	case !ok:
+		return 0, &EdgeNotFoundError{Source: id1, Target: id2}
+	}
+	// fn is called without the lock, so it may read the graph
+	return fn(nd1, nd2, weight), nil
+}
+
+func (g *graph) WeightStats() (min, max, mean, stddev float64, count int) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// Welford's online algorithm, to keep it to one pass
+	m2 := 0.0
+	for _, cmap := range g.nodeChildren {
+		for _, weight := range cmap {
+			count++
+			if count == 1 || weight < min {
+				min = weight
+			}
+			if count == 1 || weight > max {
+				max = weight
+			}
+			delta := weight - mean
+			mean += delta / float64(count)
+			m2 += delta * (weight - mean)
+		}
+	}
+	if count > 0 {
+		stddev = math.Sqrt(m2 / float64(count))
+	}
+	return min, max, mean, stddev, count
+}
+
+func (g *graph) EdgeWeight(id1, id2 ID) (float64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeExistID(id1) {
+		return 0, &NodeNotFoundError{ID: id1}
+	}
+	if !g.unsafeExistID(id2) {
+		return 0, &NodeNotFoundError{ID: id2}
+	}
+
+	if _, ok := g.nodeChildren[id1]; ok {
+		if v, ok := g.nodeChildren[id1][id2]; ok {
+			return v, nil
+		}
+	}
+	return 0.0, &EdgeNotFoundError{Source: id1, Target: id2}
+}
+
+func (g *graph) PathWeight(path []ID) (float64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if len(path) == 0 {
+		return 0, fmt.Errorf("path is empty")
+	}
+	for _, id := range path {
+		if !g.unsafeExistID(id) {
+			return 0, &NodeNotFoundError{ID: id}
+		}
+	}
+
+	total := 0.0
+	for i := 1; i < len(path); i++ {
+		w, ok := g.nodeChildren[path[i-1]][path[i]]
+		if !ok {
+			return 0, &EdgeNotFoundError{Source: path[i-1], Target: path[i]}
+		}
+		total += w
+	}
+	return total, nil
+}
+
+func (g *graph) ReverseWeight(id1, id2 ID) (float64, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeExistID(id1) {
+		return 0, &NodeNotFoundError{ID: id1}
+	}
+	if !g.unsafeExistID(id2) {
+		return 0, &NodeNotFoundError{ID: id2}
+	}
+
+	if _, ok := g.nodeParents[id2]; ok {
+		if v, ok := g.nodeParents[id2][id1]; ok {
+			return v, nil
+		}
+	}
+	return 0.0, &EdgeNotFoundError{Source: id1, Target: id2}
+}
+
+func (g *graph) ParentNodesOf(id ID) (map[ID]Node, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeExistID(id) {
+		return nil, &NodeNotFoundError{ID: id}
+	}
+
+	rs := make(map[ID]Node)
+	if _, ok := g.nodeParents[id]; ok {
+		for n := range g.nodeParents[id] {
+			rs[n] = g.nodes[n]
+		}
+	}
+	return rs, nil
+}
+
+func (g *graph) ChildNodesOf(id ID) (map[ID]Node, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeExistID(id) {
+		return nil, &NodeNotFoundError{ID: id}
+	}
+
+	rs := make(map[ID]Node)
+	if _, ok := g.nodeChildren[id]; ok {
+		for n := range g.nodeChildren[id] {
+			rs[n] = g.nodes[n]
+		}
+	}
+	return rs, nil
+}
+
+func (g *graph) ParentNodesSorted(id ID) ([]Node, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeExistID(id) {
+		return nil, &NodeNotFoundError{ID: id}
+	}
+
+	rs := make([]Node, 0, len(g.nodeParents[id]))
+	for n := range g.nodeParents[id] {
+		rs = append(rs, g.nodes[n])
+	}
+	sortNodesByString(rs)
+	return rs, nil
+}
+
+func (g *graph) ChildNodesSorted(id ID) ([]Node, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeExistID(id) {
+		return nil, &NodeNotFoundError{ID: id}
+	}
+
+	rs := make([]Node, 0, len(g.nodeChildren[id]))
+	for n := range g.nodeChildren[id] {
+		rs = append(rs, g.nodes[n])
+	}
+	sortNodesByString(rs)
+	return rs, nil
+}
+
+func (g *graph) ParentNodesFiltered(id ID, pred func(n Node, weight float64) bool) (map[ID]Node, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeExistID(id) {
+		return nil, &NodeNotFoundError{ID: id}
+	}
+
+	rs := make(map[ID]Node)
+	for n, weight := range g.nodeParents[id] {
+		if pred(g.nodes[n], weight) {
+			rs[n] = g.nodes[n]
+		}
+	}
+	return rs, nil
+}
+
+func (g *graph) ChildNodesFiltered(id ID, pred func(n Node, weight float64) bool) (map[ID]Node, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeExistID(id) {
+		return nil, &NodeNotFoundError{ID: id}
+	}
+
+	rs := make(map[ID]Node)
+	for n, weight := range g.nodeChildren[id] {
+		if pred(g.nodes[n], weight) {
+			rs[n] = g.nodes[n]
+		}
+	}
+	return rs, nil
+}
+
+func (g *graph) TopChildren(id ID, n int) ([]Edge, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("n must not be negative but %d", n)
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.unsafeExistID(id) {
+		return nil, &NodeNotFoundError{ID: id}
+	}
+
+	edges := []Edge{}
+	for id2, weight := range g.nodeChildren[id] {
+		edges = append(edges, NewEdge(g.nodes[id], g.nodes[id2], weight, nil))
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Weight() != edges[j].Weight() {
+			return edges[i].Weight() > edges[j].Weight()
+		}
+		return lessID(edges[i].Target().ID(), edges[j].Target().ID())
+	})
+	if len(edges) > n {
+		edges = edges[:n]
+	}
+	return edges, nil
+}
+
+func (g *graph) ExportToJSON(path string) map[string]map[string]map[string]float64 {
+	buf := new(bytes.Buffer)
+	if err := ExportToJSON(g, buf); err != nil {
+		return nil
+	}
+
+	// decode the same bytes that are written to the file
+	js := make(map[string]map[string]json.RawMessage)
+	if err := json.Unmarshal(buf.Bytes(), &js); err != nil {
+		return nil
+	}
+	rs := make(map[string]map[string]map[string]float64, len(js))
+	for graphID, gmap := range js {
+		rs[graphID] = make(map[string]map[string]float64, len(gmap))
+		for key, raw := range gmap {
+			if key == propsKey {
+				continue
+			}
+			tmap := make(map[string]float64)
+			if err := json.Unmarshal(raw, &tmap); err != nil {
+				return nil
+			}
+			rs[graphID][key] = tmap
+		}
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0666); err != nil {
+		return nil
+	}
+	return rs
+}
+
+func (g *graph) Edges() []Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	edges := []Edge{}
+	for id1, cmap := range g.nodeChildren {
+		for id2, weight := range cmap {
+			edges = append(edges, NewEdge(g.nodes[id1], g.nodes[id2], weight, nil))
+		}
+	}
+	sortEdges(edges)
+	return edges
+}
+
+func (g *graph) EachEdge(fn func(src, tgt ID, weight float64) bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for id1, cmap := range g.nodeChildren {
+		for id2, weight := range cmap {
+			if !fn(id1, id2, weight) {
+				return
+			}
+		}
+	}
+}
+
+// sortEdges sorts the edges by source and target IDs, then by weight.
+func sortEdges(edges []Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		src1, src2 := edges[i].Source().ID(), edges[j].Source().ID()
+		if src1 != src2 {
+			return lessID(src1, src2)
+		}
+		tgt1, tgt2 := edges[i].Target().ID(), edges[j].Target().ID()
+		if tgt1 != tgt2 {
+			return lessID(tgt1, tgt2)
+		}
+		return edges[i].Weight() < edges[j].Weight()
+	})
+}
+
+func (g *graph) String() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	edges := []Edge{}
+	for id1, cmap := range g.nodeChildren {
+		for id2, weight := range cmap {
+			edges = append(edges, NewEdge(g.nodes[id1], g.nodes[id2], weight, nil))
+		}
+	}
+	sortEdges(edges)
+
+	buf := new(bytes.Buffer)
+	for _, edge := range edges {
+		buf.WriteString(edge.String())
+	}
+	return buf.String()
+}
+
+func (g *graph) SelfLoops() []ID {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	rs := []ID{}
+	for id, cmap := range g.nodeChildren {
+		if _, ok := cmap[id]; ok {
+			rs = append(rs, id)
+		}
+	}
+	sort.Sort(idSlice(rs))
+	return rs
+}
+
+func (g *graph) Sources() []ID {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	rs := []ID{}
+	for id := range g.nodes {
+		if len(g.nodeParents[id]) == 0 {
+			rs = append(rs, id)
+		}
+	}
+	sort.Sort(idSlice(rs))
+	return rs
+}
+
+func (g *graph) Sinks() []ID {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	rs := []ID{}
+	for id := range g.nodes {
+		if len(g.nodeChildren[id]) == 0 {
+			rs = append(rs, id)
+		}
+	}
+	sort.Sort(idSlice(rs))
+	return rs
+}
+
+func (g *graph) Reverse() Graph {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	rg := newGraph()
+	rg.id = g.id
+	rg.noSelfLoops = g.noSelfLoops
+	rg.mergeEdge = g.mergeEdge
+	rg.maxNodes = g.maxNodes
+	rg.maxEdges = g.maxEdges
+	for id, nd := range g.nodes {
+		rg.nodes[id] = copyNode(nd)
+	}
+	for id1, cmap := range g.nodeChildren {
+		for id2, weight := range cmap {
+			rg.unsafeAddEdge(id2, id1, weight, nil)
+		}
+	}
+	return rg
+}
+
+func (g *graph) Subgraph(ids []ID) (Graph, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	missing := []string{}
+	for _, id := range ids {
+		if !g.unsafeExistID(id) {
+			missing = append(missing, id.String())
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s do not exist in the graph: %w", strings.Join(missing, ", "), ErrNodeNotFound)
+	}
+
+	sg := newGraph()
+	sg.id = g.id
+	sg.noSelfLoops = g.noSelfLoops
+	sg.mergeEdge = g.mergeEdge
+	sg.maxNodes = g.maxNodes
+	sg.maxEdges = g.maxEdges
+	for _, id := range ids {
+		sg.nodes[id] = copyNode(g.nodes[id])
+	}
+	for id1 := range sg.nodes {
+		for id2, weight := range g.nodeChildren[id1] {
+			if sg.unsafeExistID(id2) {
+				sg.unsafeAddEdge(id1, id2, weight, nil)
+			}
+		}
+	}
+	return sg, nil
+}
+
+func (g *graph) Threshold(min float64) Graph {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	tg := newGraph()
+	tg.id = g.id
+	tg.noSelfLoops = g.noSelfLoops
+	tg.mergeEdge = g.mergeEdge
+	tg.maxNodes = g.maxNodes
+	tg.maxEdges = g.maxEdges
+	for id, nd := range g.nodes {
+		tg.nodes[id] = copyNode(nd)
+	}
+	for id1, cmap := range g.nodeChildren {
+		for id2, weight := range cmap {
+			if weight >= min {
+				tg.unsafeAddEdge(id1, id2, weight, nil)
+			}
+		}
+	}
+	return tg
+}
+
+func (g *graph) Validate() error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	problems := []string{}
+	for id1, cmap := range g.nodeChildren {
+		if !g.unsafeExistID(id1) {
+			problems = append(problems, fmt.Sprintf("children of %s are recorded but %s does not exist in the graph", id1, id1))
+		}
+		for id2, weight := range cmap {
+			if !g.unsafeExistID(id2) {
+				problems = append(problems, fmt.Sprintf("edge from %s to %s targets %s which does not exist in the graph", id1, id2, id2))
+			}
+			v, ok := g.nodeParents[id2][id1]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("edge from %s to %s has no matching parent entry", id1, id2))
+			} else if v != weight {
+				problems = append(problems, fmt.Sprintf("edge from %s to %s has weight %.3f but its parent entry has %.3f", id1, id2, weight, v))
+			}
+		}
+	}
+	for id2, pmap := range g.nodeParents {
+		if !g.unsafeExistID(id2) {
+			problems = append(problems, fmt.Sprintf("parents of %s are recorded but %s does not exist in the graph", id2, id2))
+		}
+		for id1 := range pmap {
+			if !g.unsafeExistID(id1) {
+				problems = append(problems, fmt.Sprintf("edge from %s to %s comes from %s which does not exist in the graph", id1, id2, id1))
+			}
+			if _, ok := g.nodeChildren[id1][id2]; !ok {
+				problems = append(problems, fmt.Sprintf("parent entry from %s to %s has no matching child entry", id1, id2))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("%d inconsistencies found in the graph:\n\t* %s", len(problems), strings.Join(problems, "\n\t* "))
+}
+
+// newGraph returns a new graph configured with the options.
+func newGraph(opts ...Option) *graph {
+	g := &graph{
+		nodes:        make(map[ID]Node),
+		nodeParents:  make(map[ID]map[ID]float64),
+		nodeChildren: make(map[ID]map[ID]float64),
+		//
+		// without this
+		// panic: assignment to entry in nil map
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// NewGraph returns a new graph.
+func NewGraph(opts ...Option) Graph {
+	return newGraph(opts...)
+}
+
+// NewGraphNoSelfLoops returns a new graph that rejects
+// edges from a node to itself with ErrSelfLoop.
+func NewGraphNoSelfLoops() Graph {
+	g := newGraph()
+	g.noSelfLoops = true
+	return g
+}
+
+// Merge copies all nodes and edges from src into dst. When both graphs
+// have an edge between the same pair of nodes, onConflict returns the
+// resulting weight from the existing edge in dst and the incoming edge
+// from src. A nil onConflict keeps the weight in dst. The properties of
+// nodes in both graphs are merged, with src overwriting on key collision.
+// It returns ErrFrozen if dst was returned by Freeze, and error wrapping
+// ErrCapacityExceeded if dst cannot take all the nodes or edges.
+func Merge(dst Graph, src Graph, onConflict func(existing, incoming Edge) float64) error {
+	if _, ok := dst.(*frozenGraph); ok {
+		return ErrFrozen
+	}
+	for id, nd := range src.Nodes() {
+		if dnd, err := dst.Node(id); err == nil {
+			pn, ok := dnd.(PropNode)
+			if !ok {
+				return fmt.Errorf("properties of %s cannot be set", id)
+			}
+			for k, v := range nd.Props() {
+				pn.SetProp(k, v)
+			}
+			continue
+		}
+		if !dst.AddNode(copyNode(nd)) {
+			if dg, ok := dst.(*graph); ok && dg.nodesFull() {
+				return fmt.Errorf("%s could not be added: %w", id, ErrCapacityExceeded)
+			}
+			return fmt.Errorf("%s could not be added to the graph", id)
+		}
+	}
+
+	for id1, nd1 := range src.Nodes() {
+		cmap, err := src.ChildNodesOf(id1)
+		if err != nil {
+			return err
+		}
+		for id2, nd2 := range cmap {
+			weight, err := src.EdgeWeight(id1, id2)
+			if err != nil {
+				return err
+			}
+			existing, err := dst.EdgeWeight(id1, id2)
+			if err != nil {
+				if err := dst.ReplaceEdge(id1, id2, weight); err != nil {
+					return err
+				}
+				continue
+			}
+			if onConflict == nil {
+				continue
+			}
+			dnd1, err := dst.Node(id1)
+			if err != nil {
+				return err
+			}
+			dnd2, err := dst.Node(id2)
+			if err != nil {
+				return err
+			}
+			weight = onConflict(
+				NewEdge(dnd1, dnd2, existing, make(map[string]string)),
+				NewEdge(nd1, nd2, weight, make(map[string]string)),
+			)
+			if err := dst.ReplaceEdge(id1, id2, weight); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EqualEpsilon is the largest absolute difference between two node
+// or edge weights that Equal still treats as the same weight.
+const EqualEpsilon = 1e-9
+
+// Equal returns true if both graphs have the same node IDs, the same
+// node properties, and the same edges, where node and edge weights
+// differ by at most EqualEpsilon. Graph IDs and edge properties are
+// not compared.
+func Equal(a, b Graph) bool {
+	anodes, bnodes := a.Nodes(), b.Nodes()
+	if len(anodes) != len(bnodes) {
+		return false
+	}
+	for id, and := range anodes {
+		bnd, ok := bnodes[id]
+		if !ok {
+			return false
+		}
+		if math.Abs(nodeWeight(and)-nodeWeight(bnd)) > EqualEpsilon {
+			return false
+		}
+		aprops, bprops := and.Props(), bnd.Props()
+		if len(aprops) != len(bprops) {
+			return false
+		}
+		for k, v := range aprops {
+			if w, ok := bprops[k]; !ok || v != w {
+				return false
+			}
+		}
+
+		acmap, err := a.ChildNodesOf(id)
+		if err != nil {
+			return false
+		}
+		bcmap, err := b.ChildNodesOf(id)
+		if err != nil || len(acmap) != len(bcmap) {
+			return false
+		}
+		for id2 := range acmap {
+			aw, err := a.EdgeWeight(id, id2)
+			if err != nil {
+				return false
+			}
+			bw, err := b.EdgeWeight(id, id2)
+			if err != nil || math.Abs(aw-bw) > EqualEpsilon {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// NewGraphFromJSON returns a new Graph from a JSON file.
+// A weight is a JSON number, or a string holding a number
+// such as "14". A node ID that is a reserved key such as
+// "_props" or "_weights" returns error wrapping ErrReservedID.
+// Here's the sample JSON data:
+//
+//	{
+//	    "graph_00": {
+//	        "S": {
+//	            "A": 100,
+//	            "B": 14,
+//	            "C": 200
+//	        },
+//	        "A": {
+//	            "S": 15,
+//	            "B": 5,
+//	            "D": 20,
+//	            "T": 44
+//	        },
+//	        "B": {
+//	            "S": 14,
+//	            "A": 5,
+//	            "D": 30,
+//	            "E": 18
+//	        },
+//	        "C": {
+//	            "S": 9,
+//	            "E": 24
+//	        },
+//	        "D": {
+//	            "A": 20,
+//	            "B": 30,
+//	            "E": 2,
+//	            "F": 11,
+//	            "T": 16
+//	        },
+//	        "E": {
+//	            "B": 18,
+//	            "C": 24,
+//	            "D": 2,
+//	            "F": 6,
+//	            "T": 19
+//	        },
+//	        "F": {
+//	            "D": 11,
+//	            "E": 6,
+//	            "T": 6
+//	        },
+//	        "T": {
+//	            "A": 44,
+//	            "D": 16,
+//	            "F": 6,
+//	            "E": 19
+//	        }
+//	    },
+//	}
+//
+func NewGraphFromJSON(rd io.Reader, graphID string, opts ...Option) (Graph, error) {
+	js := make(map[string]map[string]json.RawMessage)
+	dec := json.NewDecoder(rd)
+	for {
+		if err := dec.Decode(&js); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := js[graphID]; !ok {
+		return nil, fmt.Errorf("%s does not exist", graphID)
+	}
+	gmap := js[graphID]
+
+	g := newGraph(opts...)
+	g.id = graphID
+	for _, id1 := range sortedKeys(gmap) {
+		raw := gmap[id1]
+		if id1 == propsKey {
+			pmap, err := decodeJSONProps(raw)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range sortedKeys(pmap) {
+				props := pmap[id]
+				if err := g.loadTypedProps(id, props); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if id1 == weightsKey {
+			if err := g.loadJSONWeights(raw); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		mm := make(map[string]interface{})
+		if err := json.Unmarshal(raw, &mm); err != nil {
+			return nil, err
+		}
+		nd1, err := g.loadKeyedNode(id1)
+		if err != nil {
+			return nil, err
+		}
+		for _, id2 := range sortedKeys(mm) {
+			v := mm[id2]
+			weight, ok := parseJSONWeight(v)
+			if !ok {
+				return nil, fmt.Errorf("weight from %s to %s must be a number but %v", id1, id2, v)
+			}
+			nd2, err := g.loadKeyedNode(id2)
+			if err != nil {
+				return nil, err
+			}
+			if err := g.ReplaceEdge(nd1.ID(), nd2.ID(), weight); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return g, nil
+}
+
+// parseJSONWeight returns the weight decoded from a JSON number,
+// or parsed from a JSON string, and false if v is not numeric.
+func parseJSONWeight(v interface{}) (float64, bool) {
+	switch w := v.(type) {
+	case float64:
+		return w, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(w), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, false
+		}
+		return f, true
+	}
+	return 0, false
+}
+
+// weightsKey is the reserved key holding node weights in a JSON
+// or YAML graph. It maps each node ID to its weight, and nodes
+// that are not listed have weight 0:
+//
+//	{
+//	    "graph_00": {
+//	        "_weights": {
+//	            "S": 2.5
+//	        },
+//	        "S": {
+//	            "A": 100
+//	        }
+//	    }
+//	}
+const weightsKey = "_weights"
+
+// loadJSONWeights sets the node weights from the "_weights" section.
+func (g *graph) loadJSONWeights(raw json.RawMessage) error {
+	wmap := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &wmap); err != nil {
+		return err
+	}
+	for _, id := range sortedKeys(wmap) {
+		v := wmap[id]
+		weight, ok := parseJSONWeight(v)
+		if !ok {
+			return fmt.Errorf("weight of %s must be a number but %v", id, v)
+		}
+		if err := g.loadWeight(id, weight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadWeight sets the weight of the node with the id,
+// adding the node if it does not exist yet.
+func (g *graph) loadWeight(id string, weight float64) error {
+	nd, err := g.loadKeyedNode(id)
+	if err != nil {
+		return err
+	}
+	if n, ok := nd.(*node); ok {
+		n.setWeight(weight)
+	}
+	return nil
+}
+
+// propsKey is the reserved key holding node properties in a JSON graph.
+// It maps each node ID to its properties:
+//
+//	{
+//	    "graph_00": {
+//	        "_props": {
+//	            "S": {"label": "source"}
+//	        },
+//	        "S": {
+//	            "A": 100
+//	        }
+//	    }
+//	}
+const propsKey = "_props"
+
+// reservedKeys are the keys of a JSON or YAML graph that hold
+// sections instead of nodes, so they cannot be used as node IDs.
+var reservedKeys = map[string]bool{
+	propsKey:   true,
+	weightsKey: true,
+}
+
+// checkReservedID returns error wrapping ErrReservedID
+// if the id is one of reservedKeys.
+func checkReservedID(id string) error {
+	if reservedKeys[id] {
+		return fmt.Errorf("%w: %q", ErrReservedID, id)
+	}
+	return nil
+}
+
+// decodeJSONProps decodes the "_props" section. Numbers become int
+// if they are integers, and float64 otherwise.
+func decodeJSONProps(raw json.RawMessage) (map[string]map[string]interface{}, error) {
+	pmap := make(map[string]map[string]interface{})
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&pmap); err != nil {
+		return nil, err
 	}
-	if !g.unsafeExistID(id2) {
-		return fmt.Errorf("%s does not exist in the graph", id2)
+	for _, props := range pmap {
+		if err := convertJSONNumbers(props); err != nil {
+			return nil, err
+		}
 	}
+	return pmap, nil
+}
 
-	if _, ok := g.nodeChildren[id1]; ok {
-		if _, ok := g.nodeChildren[id1][id2]; ok {
-			delete(g.nodeChildren[id1], id2)
+// convertJSONNumbers replaces the json.Number values in props
+// with an int when they are integers, and a float64 otherwise.
+func convertJSONNumbers(props map[string]interface{}) error {
+	for k, v := range props {
+		num, ok := v.(json.Number)
+		if !ok {
+			continue
 		}
-	}
-	if _, ok := g.nodeParents[id2]; ok {
-		if _, ok := g.nodeParents[id2][id1]; ok {
-			delete(g.nodeParents[id2], id1)
+		if i, err := num.Int64(); err == nil {
+			props[k] = int(i)
+		} else if f, err := num.Float64(); err == nil {
+			props[k] = f
+		} else {
+			return err
 		}
 	}
 	return nil
 }
 
-func (g *graph) EdgeWeight(id1, id2 ID) (float64, error) {
-	g.mu.RLock()
-	defer g.mu.RUnlock()
-
-	if !g.unsafeExistID(id1) {
-		return 0, fmt.Errorf("%s does not exist in the graph", id1)
-	}
-	if !g.unsafeExistID(id2) {
-		return 0, fmt.Errorf("%s does not exist in the graph", id2)
+// loadNode returns the node with the id, adding a new node
+// without properties if it does not exist yet.
+func (g *graph) loadNode(id string) (Node, error) {
+	nid, err := g.parseID(id)
+	if err != nil {
+		return nil, err
 	}
-
-	if _, ok := g.nodeChildren[id1]; ok {
-		if v, ok := g.nodeChildren[id1][id2]; ok {
-			return v, nil
+	nd, err := g.Node(nid)
+	if err != nil {
+		nd = NewNodeWithID(nid, nil)
+		if !g.AddNode(nd) {
+			return nil, fmt.Errorf("%w: at most %d nodes", ErrCapacityExceeded, g.maxNodes)
 		}
 	}
-	return 0.0, fmt.Errorf("there is no edge from %s to %s", id1, id2)
+	return nd, nil
 }
 
-func (g *graph) ParentNodesOf(id ID) (map[ID]Node, error) {
-	g.mu.RLock()
-	defer g.mu.RUnlock()
-
-	if !g.unsafeExistID(id) {
-		return nil, fmt.Errorf("%s does not exist in the graph", id)
+// loadKeyedNode is loadNode for the JSON and YAML formats,
+// which reject IDs that are reserved keys.
+func (g *graph) loadKeyedNode(id string) (Node, error) {
+	if err := checkReservedID(id); err != nil {
+		return nil, err
 	}
+	return g.loadNode(id)
+}
 
-	rs := make(map[ID]Node)
-	if _, ok := g.nodeParents[id]; ok {
-		for n := range g.nodeParents[id] {
-			rs[n] = g.nodes[n]
+// parseID returns the ID of a loaded node, which
+// is an IntID if the graph was created WithIntIDs.
+func (g *graph) parseID(id string) (ID, error) {
+	if g.validateID != nil {
+		if err := g.validateID(id); err != nil {
+			return nil, fmt.Errorf("invalid ID %q: %w", id, err)
 		}
 	}
-	return rs, nil
+	if !g.intIDs {
+		return StringID(id), nil
+	}
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid integer ID %q", id)
+	}
+	return IntID(i), nil
 }
 
-func (g *graph) ChildNodesOf(id ID) (map[ID]Node, error) {
-	g.mu.RLock()
-	defer g.mu.RUnlock()
+// loadProps sets the properties on the node with the id,
+// adding the node if it does not exist yet.
+func (g *graph) loadProps(id string, props map[string]string) error {
+	nd, err := g.loadNode(id)
+	if err != nil {
+		return err
+	}
+	pn, ok := nd.(PropNode)
+	if !ok {
+		return fmt.Errorf("properties of %s cannot be set", id)
+	}
+	for k, v := range props {
+		pn.SetProp(k, v)
+	}
+	return nil
+}
 
-	if !g.unsafeExistID(id) {
-		return nil, fmt.Errorf("%s does not exist in the graph", id)
+// loadTypedProps sets the properties on the node with the id like
+// loadProps, but keeps values that are not strings typed.
+func (g *graph) loadTypedProps(id string, props map[string]interface{}) error {
+	nd, err := g.loadKeyedNode(id)
+	if err != nil {
+		return err
+	}
+	n, ok := nd.(*node)
+	if !ok {
+		return fmt.Errorf("properties of %s cannot be set", id)
 	}
+	for k, v := range props {
+		n.setTypedProp(k, v)
+	}
+	return nil
+}
 
-	rs := make(map[ID]Node)
-	if _, ok := g.nodeChildren[id]; ok {
-		for n := range g.nodeChildren[id] {
-			rs[n] = g.nodes[n]
+// ExportToJSON writes the graph to w in the format read by
+// NewGraphFromJSON, keyed by the graph ID. Node properties are
+// written to the "_props" section, and node weights other than 0 to
+// the "_weights" section, which are omitted when empty. Nodes without
+// outgoing edges are written with an empty object so that they
+// survive the round trip. It returns error wrapping ErrReservedID
+// if a node ID is a reserved key such as "_props" or "_weights",
+// which could not be read back.
+func ExportToJSON(g Graph, w io.Writer) error {
+	gmap := make(map[string]interface{})
+	pmap := make(map[string]interface{})
+	wmap := make(map[string]float64)
+	for id1, nd1 := range g.Nodes() {
+		if err := checkReservedID(id1.String()); err != nil {
+			return err
+		}
+		if weight := nodeWeight(nd1); weight != 0 {
+			wmap[id1.String()] = weight
+		}
+		if tnd, ok := nd1.(TypedNode); ok && len(nd1.Props()) > 0 {
+			pmap[id1.String()] = tnd.TypedProps()
+		} else if len(nd1.Props()) > 0 {
+			pmap[id1.String()] = nd1.Props()
+		}
+		cmap, err := g.ChildNodesOf(id1)
+		if err != nil {
+			return err
+		}
+		tmap := make(map[string]float64)
+		for id2 := range cmap {
+			weight, err := g.EdgeWeight(id1, id2)
+			if err != nil {
+				return err
+			}
+			tmap[id2.String()] = weight
 		}
+		gmap[id1.String()] = tmap
+	}
+	if len(pmap) > 0 {
+		gmap[propsKey] = pmap
+	}
+	if len(wmap) > 0 {
+		gmap[weightsKey] = wmap
 	}
-	return rs, nil
-}
 
-func (g *graph) ExportToJSON(path string) map[string]map[string]map[string]float64 {
-	panic("Not implemented")
+	return json.NewEncoder(w).Encode(map[string]interface{}{g.ID().String(): gmap})
 }
 
-func (g *graph) String() string {
+// StreamJSON writes the keys in the sorted order used by encoding/json
+// for maps, so the output matches ExportToJSON. Only the children or
+// properties of one node are encoded at a time. It holds the read lock
+// until the whole graph is written.
+func (g *graph) StreamJSON(w io.Writer) error {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
+	bw := bufio.NewWriter(w)
 	buf := new(bytes.Buffer)
-	for id1, nd1 := range g.nodes {
-		nmap, _ := g.ChildNodesOf(id1)
-		for id2, nd2 := range nmap {
-			weight, _ := g.EdgeWeight(id1, id2)
-			fmt.Fprintf(buf, "%s -- %.3f -→ %s\n", nd1, weight, nd2)
+	enc := json.NewEncoder(buf)
+	// write encodes v without the newline added by the encoder
+	write := func(v interface{}) error {
+		buf.Reset()
+		if err := enc.Encode(v); err != nil {
+			return err
 		}
+		_, err := bw.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+		return err
 	}
-	return buf.String()
+
+	keys := make([]string, 0, len(g.nodes)+2)
+	ids := make(map[string]ID, len(g.nodes))
+	hasProps := false
+	wmap := make(map[string]float64)
+	for id, nd := range g.nodes {
+		if err := checkReservedID(id.String()); err != nil {
+			return err
+		}
+		keys = append(keys, id.String())
+		ids[id.String()] = id
+		if len(nd.Props()) > 0 {
+			hasProps = true
+		}
+		if weight := nodeWeight(nd); weight != 0 {
+			wmap[id.String()] = weight
+		}
+	}
+	if hasProps {
+		keys = append(keys, propsKey)
+	}
+	if len(wmap) > 0 {
+		keys = append(keys, weightsKey)
+	}
+	sort.Strings(keys)
+
+	bw.WriteByte('{')
+	if err := write(g.id); err != nil {
+		return err
+	}
+	bw.WriteString(":{")
+	for i, key := range keys {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		if err := write(key); err != nil {
+			return err
+		}
+		bw.WriteByte(':')
+
+		if hasProps && key == propsKey {
+			if err := g.unsafeStreamProps(bw, write, keys, ids); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(wmap) > 0 && key == weightsKey {
+			if err := write(wmap); err != nil {
+				return err
+			}
+			continue
+		}
+		tmap := make(map[string]float64, len(g.nodeChildren[ids[key]]))
+		for id2, weight := range g.nodeChildren[ids[key]] {
+			tmap[id2.String()] = weight
+		}
+		if err := write(tmap); err != nil {
+			return err
+		}
+	}
+	bw.WriteString("}}\n")
+	return bw.Flush()
 }
 
-// newGraph returns a new graph.
-func newGraph() *graph {
-	return &graph{
-		nodes:        make(map[ID]Node),
-		nodeParents:  make(map[ID]map[ID]float64),
-		nodeChildren: make(map[ID]map[ID]float64),
-		//
-		// without this
-		// panic: assignment to entry in nil map
+// unsafeStreamProps writes the "_props" object of StreamJSON.
+func (g *graph) unsafeStreamProps(bw *bufio.Writer, write func(interface{}) error, keys []string, ids map[string]ID) error {
+	bw.WriteByte('{')
+	first := true
+	for _, key := range keys {
+		nd, ok := g.nodes[ids[key]]
+		if !ok || len(nd.Props()) == 0 {
+			continue
+		}
+		if !first {
+			bw.WriteByte(',')
+		}
+		first = false
+		if err := write(key); err != nil {
+			return err
+		}
+		bw.WriteByte(':')
+		var props interface{} = nd.Props()
+		if tnd, ok := nd.(TypedNode); ok {
+			props = tnd.TypedProps()
+		}
+		if err := write(props); err != nil {
+			return err
+		}
 	}
+	bw.WriteByte('}')
+	return nil
 }
 
-// NewGraph returns a new graph.
-func NewGraph() Graph {
-	return newGraph()
+// NewGraphFromJSONStream returns a new Graph from a JSON file in the same
+// format as NewGraphFromJSON, but reads it token by token. Only the graph
+// with graphID is built, and other graphs are skipped without being
+// decoded, so memory stays proportional to the target graph.
+// Malformed JSON returns an error with the byte offset of the problem.
+func NewGraphFromJSONStream(rd io.Reader, graphID string, opts ...Option) (Graph, error) {
+	return newGraphFromJSONStream(rd, graphID, false, opts)
 }
 
-// NewGraphFromJSON returns a new Graph from a JSON file.
-// Here's the sample JSON data:
-//
-//	{
-//	    "graph_00": {
-//	        "S": {
-//	            "A": 100,
-//	            "B": 14,
-//	            "C": 200
-//	        },
-//	        "A": {
-//	            "S": 15,
-//	            "B": 5,
-//	            "D": 20,
-//	            "T": 44
-//	        },
-//	        "B": {
-//	            "S": 14,
-//	            "A": 5,
-//	            "D": 30,
-//	            "E": 18
-//	        },
-//	        "C": {
-//	            "S": 9,
-//	            "E": 24
-//	        },
-//	        "D": {
-//	            "A": 20,
-//	            "B": 30,
-//	            "E": 2,
-//	            "F": 11,
-//	            "T": 16
-//	        },
-//	        "E": {
-//	            "B": 18,
-//	            "C": 24,
-//	            "D": 2,
-//	            "F": 6,
-//	            "T": 19
-//	        },
-//	        "F": {
-//	            "D": 11,
-//	            "E": 6,
-//	            "T": 6
-//	        },
-//	        "T": {
-//	            "A": 44,
-//	            "D": 16,
-//	            "F": 6,
-//	            "E": 19
-//	        }
-//	    },
-//	}
-//
-func NewGraphFromJSON(rd io.Reader, graphID string) (Graph, error) {
-	js := make(map[string]map[string]map[string]float64)
+// NewGraphFromJSONStrict returns a new Graph from a JSON file in the same
+// format as NewGraphFromJSON, but returns an error wrapping
+// ErrDuplicateEdge when an edge from a source to a target is defined more
+// than once, either within one source object or across repeated source
+// or graph keys, instead of keeping the last weight.
+func NewGraphFromJSONStrict(rd io.Reader, graphID string, opts ...Option) (Graph, error) {
+	return newGraphFromJSONStream(rd, graphID, true, opts)
+}
+
+func newGraphFromJSONStream(rd io.Reader, graphID string, strict bool, opts []Option) (Graph, error) {
 	dec := json.NewDecoder(rd)
+
+	var g *graph
 	for {
-		if err := dec.Decode(&js); err == io.EOF {
+		if _, err := dec.Token(); err == io.EOF {
 			break
 		} else if err != nil {
+			return nil, jsonStreamError(dec, err)
+		}
+		for dec.More() {
+			key, err := jsonStreamString(dec)
+			if err != nil {
+				return nil, err
+			}
+			if key != graphID {
+				if err := jsonStreamSkip(dec); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if g == nil || !strict {
+				g = newGraph(opts...)
+				g.id = graphID
+			}
+			if err := jsonStreamGraph(dec, g, strict); err != nil {
+				return nil, err
+			}
+		}
+		if err := jsonStreamDelim(dec, '}'); err != nil {
 			return nil, err
 		}
 	}
-	if _, ok := js[graphID]; !ok {
+	if g == nil {
 		return nil, fmt.Errorf("%s does not exist", graphID)
 	}
-	gmap := js[graphID]
 
-	g := newGraph()
-	for id1, mm := range gmap {
-		nd1, err := g.Node(StringID(id1))
+	return g, nil
+}
+
+// jsonStreamGraph reads a graph object of the form
+// {"source": {"target": weight}} into g. If strict, an edge
+// that already exists in g is an error.
+func jsonStreamGraph(dec *json.Decoder, g *graph, strict bool) error {
+	if err := jsonStreamDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		id1, err := jsonStreamString(dec)
+		if err != nil {
+			return err
+		}
+		if id1 == propsKey {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return jsonStreamError(dec, err)
+			}
+			pmap, err := decodeJSONProps(raw)
+			if err != nil {
+				return jsonStreamError(dec, err)
+			}
+			for _, id := range sortedKeys(pmap) {
+				props := pmap[id]
+				if err := g.loadTypedProps(id, props); err != nil {
+					return jsonStreamError(dec, err)
+				}
+			}
+			continue
+		}
+		if id1 == weightsKey {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return jsonStreamError(dec, err)
+			}
+			if err := g.loadJSONWeights(raw); err != nil {
+				return jsonStreamError(dec, err)
+			}
+			continue
+		}
+		nd1, err := g.loadKeyedNode(id1)
 		if err != nil {
-			nd1 = NewNode(id1, make(map[string]string))
-			g.AddNode(nd1)
+			return jsonStreamError(dec, err)
+		}
+		if err := jsonStreamDelim(dec, '{'); err != nil {
+			return err
 		}
-		for id2, weight := range mm {
-			nd2, err := g.Node(StringID(id2))
+		for dec.More() {
+			id2, err := jsonStreamString(dec)
+			if err != nil {
+				return err
+			}
+			tok, err := dec.Token()
 			if err != nil {
-				nd2 = NewNode(id2, make(map[string]string))
-				g.AddNode(nd2)
+				return jsonStreamError(dec, err)
 			}
-			g.ReplaceEdge(nd1.ID(), nd2.ID(), weight)
+			weight, ok := parseJSONWeight(tok)
+			if !ok {
+				return jsonStreamError(dec, fmt.Errorf("weight from %s to %s must be a number but %v", id1, id2, tok))
+			}
+			nd2, err := g.loadKeyedNode(id2)
+			if err != nil {
+				return jsonStreamError(dec, err)
+			}
+			if strict && g.HasEdge(nd1.ID(), nd2.ID()) {
+				return fmt.Errorf("%w from %s to %s at byte offset %d", ErrDuplicateEdge, id1, id2, dec.InputOffset())
+			}
+			if err := g.ReplaceEdge(nd1.ID(), nd2.ID(), weight); err != nil {
+				return fmt.Errorf("%w at byte offset %d", err, dec.InputOffset())
+			}
+		}
+		if err := jsonStreamDelim(dec, '}'); err != nil {
+			return err
 		}
 	}
+	return jsonStreamDelim(dec, '}')
+}
 
-	return g, nil
+// jsonStreamSkip consumes the next JSON value without decoding it.
+func jsonStreamSkip(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return jsonStreamError(dec, err)
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// jsonStreamString reads the next token, which must be a string.
+func jsonStreamString(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", jsonStreamError(dec, err)
+	}
+	s, ok := tok.(string)
+	if !ok {
+		return "", jsonStreamError(dec, fmt.Errorf("expected a string but %v", tok))
+	}
+	return s, nil
+}
+
+// jsonStreamDelim reads the next token, which must be the delimiter d.
+func jsonStreamDelim(dec *json.Decoder, d json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return jsonStreamError(dec, err)
+	}
+	if tok != d {
+		return jsonStreamError(dec, fmt.Errorf("expected %s but %v", d, tok))
+	}
+	return nil
+}
+
+func jsonStreamError(dec *json.Decoder, err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("invalid JSON at byte offset %d: %w", dec.InputOffset(), err)
 }
 
 // NewGraphFromYAML returns a new Graph from a YAML file.
@@ -612,48 +2654,169 @@ func NewGraphFromJSON(rd io.Reader, graphID string) (Graph, error) {
 //     F: 6
 //     E: 19
 //
-func NewGraphFromYAML(rd io.Reader, graphID string) (Graph, error) {
-	js := make(map[string]map[string]map[string]float64)
-	var data []byte
-	d := make([]byte, 1024)
-	for {
-		n, err := rd.Read(d)
-		if err == io.EOF {
-			break
-		}
-
-		data = append(data, d[0:n]...)
+// Node properties can be set under the reserved "_props" key,
+// as with NewGraphFromJSON. Values that are not strings are
+// kept typed, see TypedNode. Node weights can be set under
+// the reserved "_weights" key.
+//
+func NewGraphFromYAML(rd io.Reader, graphID string, opts ...Option) (Graph, error) {
+	js := make(map[string]map[string]map[string]interface{})
+	data, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, err
 	}
-
-	err := yaml.Unmarshal(data, &js)
-	for {
-		if err != nil {
-			return nil, err
-		} else {
-			break
-		}
+	if err := yaml.Unmarshal(data, &js); err != nil {
+		return nil, err
 	}
 	if _, ok := js[graphID]; !ok {
 		return nil, fmt.Errorf("%s does not exist", graphID)
 	}
 	gmap := js[graphID]
 
-	g := newGraph()
-	for id1, mm := range gmap {
-		nd1, err := g.Node(StringID(id1))
+	g := newGraph(opts...)
+	g.id = graphID
+	for _, id1 := range sortedKeys(gmap) {
+		mm := gmap[id1]
+		if id1 == propsKey {
+			for _, id := range sortedKeys(mm) {
+				props := mm[id]
+				pmap, ok := props.(map[interface{}]interface{})
+				if !ok && props != nil {
+					return nil, fmt.Errorf("properties of %s must be a map but %v", id, props)
+				}
+				typed := make(map[string]interface{})
+				for k, v := range pmap {
+					typed[fmt.Sprint(k)] = v
+				}
+				if err := g.loadTypedProps(id, typed); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if id1 == weightsKey {
+			for _, id := range sortedKeys(mm) {
+				v := mm[id]
+				weight, ok := yamlWeight(v)
+				if !ok {
+					return nil, fmt.Errorf("weight of %s must be a number but %v", id, v)
+				}
+				if err := g.loadWeight(id, weight); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		nd1, err := g.loadKeyedNode(id1)
 		if err != nil {
-			nd1 = NewNode(id1, make(map[string]string))
-			g.AddNode(nd1)
+			return nil, err
 		}
-		for id2, weight := range mm {
-			nd2, err := g.Node(StringID(id2))
+		for _, id2 := range sortedKeys(mm) {
+			v := mm[id2]
+			weight, ok := yamlWeight(v)
+			if !ok {
+				return nil, fmt.Errorf("weight from %s to %s must be a number but %v", id1, id2, v)
+			}
+			nd2, err := g.loadKeyedNode(id2)
 			if err != nil {
-				nd2 = NewNode(id2, make(map[string]string))
-				g.AddNode(nd2)
+				return nil, err
+			}
+			if err := g.ReplaceEdge(nd1.ID(), nd2.ID(), weight); err != nil {
+				return nil, err
 			}
-			g.ReplaceEdge(nd1.ID(), nd2.ID(), weight)
 		}
 	}
 
 	return g, nil
 }
+
+// yamlWeight returns the weight decoded from a YAML number,
+// and false if v is not a number.
+func yamlWeight(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// ExportToYAML writes the graph to w in the format read by
+// NewGraphFromYAML, keyed by the graph ID. Nodes and edges are
+// written in sorted order so the output is stable across runs.
+// Node properties and weights are written to the "_props" and
+// "_weights" sections as with ExportToJSON. Nodes without outgoing
+// edges are written with an empty map. It returns error wrapping
+// ErrReservedID if a node ID is a reserved key.
+func ExportToYAML(g Graph, w io.Writer) error {
+	nodes := g.Nodes()
+	pmap := yaml.MapSlice{}
+	wmap := yaml.MapSlice{}
+	for _, id := range sortedIDs(nodes) {
+		if err := checkReservedID(id.String()); err != nil {
+			return err
+		}
+		nd := nodes[id]
+		if weight := nodeWeight(nd); weight != 0 {
+			wmap = append(wmap, yaml.MapItem{Key: id.String(), Value: weight})
+		}
+		if len(nd.Props()) == 0 {
+			continue
+		}
+		var props map[string]interface{}
+		if tnd, ok := nd.(TypedNode); ok {
+			props = tnd.TypedProps()
+		} else {
+			props = make(map[string]interface{})
+			for k, v := range nd.Props() {
+				props[k] = v
+			}
+		}
+		keys := make([]string, 0, len(props))
+		for k := range props {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		kmap := yaml.MapSlice{}
+		for _, k := range keys {
+			kmap = append(kmap, yaml.MapItem{Key: k, Value: props[k]})
+		}
+		pmap = append(pmap, yaml.MapItem{Key: id.String(), Value: kmap})
+	}
+
+	gmap := yaml.MapSlice{}
+	if len(pmap) > 0 {
+		gmap = append(gmap, yaml.MapItem{Key: propsKey, Value: pmap})
+	}
+	if len(wmap) > 0 {
+		gmap = append(gmap, yaml.MapItem{Key: weightsKey, Value: wmap})
+	}
+	for _, id1 := range sortedIDs(nodes) {
+		cmap, err := g.ChildNodesOf(id1)
+		if err != nil {
+			return err
+		}
+		tmap := yaml.MapSlice{}
+		for _, id2 := range sortedIDs(cmap) {
+			weight, err := g.EdgeWeight(id1, id2)
+			if err != nil {
+				return err
+			}
+			tmap = append(tmap, yaml.MapItem{Key: id2.String(), Value: weight})
+		}
+		gmap = append(gmap, yaml.MapItem{Key: id1.String(), Value: tmap})
+	}
+
+	data, err := yaml.Marshal(yaml.MapSlice{{Key: g.ID().String(), Value: gmap}})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
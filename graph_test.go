@@ -1,9 +1,21 @@
 package goraph
 
 import (
+	"bytes"
+	"encoding/gob"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/rand"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"unicode"
 
 	"goraph/testgraph"
 )
@@ -134,11 +146,41 @@ func TestGraph_Init(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
+		before := g.String()
+		count := g.NodeCount()
+
+		// a populated graph is not cleared
 		g.Init()
-		if g.NodeCount() != 0 {
-			t.Fatalf("not initialized: %s", g)
+		if g.NodeCount() != count || g.String() != before {
+			t.Fatalf("Expected Init to keep %s but %s", before, g)
+		}
+
+		g.Reset()
+		if g.NodeCount() != 0 || len(g.Edges()) != 0 {
+			t.Fatalf("not reset: %s", g)
+		}
+		if g.ID() != StringID(tg.Name) {
+			t.Fatalf("Expected ID %s but %s", tg.Name, g.ID())
 		}
 	}
+
+	var g graph
+	g.Init()
+	if !g.AddNode(NewNode("A", nil)) || g.NodeCount() != 1 {
+		t.Fatalf("not initialized: %s", &g)
+	}
+
+	g2 := NewGraph(WithMaxNodes(1))
+	deleted := []GraphEvent{}
+	g2.Subscribe(func(ev GraphEvent) { deleted = append(deleted, ev) })
+	g2.AddNode(NewNode("A", nil))
+	g2.Reset()
+	if !g2.AddNode(NewNode("B", nil)) || g2.AddNode(NewNode("C", nil)) {
+		t.Fatalf("Expected Reset to keep the capacity but %s", g2)
+	}
+	if len(deleted) != 3 || deleted[1] != (GraphEvent{Type: NodeDeleted, ID: StringID("A")}) {
+		t.Fatalf("Expected NodeDeleted for A but %v", deleted)
+	}
 }
 
 func TestGraph_DeleteNode(t *testing.T) {
@@ -202,8 +244,8 @@ func TestGraph_DeleteEdge(t *testing.T) {
 		t.Fatalf("Expected 4 edges incoming to D but %v\n\n%s", err, g)
 	}
 
-	if err := g.DeleteEdge(StringID("B"), StringID("C")); err != nil {
-		t.Fatal(err)
+	if err := g.DeleteEdge(StringID("B"), StringID("C")); !errors.Is(err, ErrEdgeNotFound) {
+		t.Fatalf("Expected ErrEdgeNotFound but %v", err)
 	}
 	if err := g.DeleteEdge(StringID("S"), StringID("C")); err != nil {
 		t.Fatal(err)
@@ -250,3 +292,1986 @@ func TestGraph_ReplaceEdge(t *testing.T) {
 		t.Fatalf("weight from C to S must be 1.0 but %v\n\n%v", err, g)
 	}
 }
+
+func TestGraph_Validate(t *testing.T) {
+	for _, tg := range testgraph.GraphSlice {
+		f, err := os.Open("testdata/graph.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		g, err := NewGraphFromJSON(f, tg.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Validate(); err != nil {
+			t.Fatalf("%s | Expected a valid graph but %v", tg.Name, err)
+		}
+		g.DeleteNode(StringID("A"))
+		if err := g.Validate(); err != nil {
+			t.Fatalf("%s | Expected a valid graph after DeleteNode but %v", tg.Name, err)
+		}
+	}
+
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	jg, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := jg.(*graph)
+	g.nodeChildren[StringID("S")][StringID("A")] = 1.0
+	delete(g.nodeParents[StringID("C")], StringID("S"))
+	g.nodeChildren[StringID("S")][StringID("X")] = 1.0
+	err = g.Validate()
+	if err == nil {
+		t.Fatal("Expected inconsistencies but got nil")
+	}
+	for _, s := range []string{
+		"4 inconsistencies",
+		"edge from S to A has weight 1.000 but its parent entry has 100.000",
+		"edge from S to C has no matching parent entry",
+		"edge from S to X targets X which does not exist in the graph",
+		"edge from S to X has no matching parent entry",
+	} {
+		if !strings.Contains(err.Error(), s) {
+			t.Fatalf("Expected %q in %v", s, err)
+		}
+	}
+}
+
+func TestGraph_PathWeight(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, distance, err := Dijkstra(g, StringID("S"), StringID("T"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := g.PathWeight(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w != distance[StringID("T")] {
+		t.Fatalf("Expected %f for %v but %f", distance[StringID("T")], path, w)
+	}
+
+	if w, err := g.PathWeight([]ID{StringID("S")}); err != nil || w != 0 {
+		t.Fatalf("Expected 0 for a single node but %f, %v", w, err)
+	}
+	if _, err := g.PathWeight([]ID{StringID("S"), StringID("T")}); !errors.Is(err, ErrEdgeNotFound) {
+		t.Fatalf("Expected ErrEdgeNotFound but %v", err)
+	}
+	if _, err := g.PathWeight([]ID{StringID("S"), StringID("X")}); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+	if _, err := g.PathWeight(nil); err == nil {
+		t.Fatal("Expected an error for an empty path")
+	}
+}
+
+func TestGraph_ReverseWeight(t *testing.T) {
+	for _, tg := range testgraph.GraphSlice {
+		f, err := os.Open("testdata/graph.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		g, err := NewGraphFromJSON(f, tg.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, edge := range g.Edges() {
+			src, tgt := edge.Source().ID(), edge.Target().ID()
+			w, err := g.EdgeWeight(src, tgt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rw, err := g.ReverseWeight(src, tgt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if w != rw {
+				t.Fatalf("%s | Expected %f from %s to %s but %f", tg.Name, w, src, tgt, rw)
+			}
+		}
+	}
+
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	jg, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := jg.(*graph)
+	g.nodeChildren[StringID("S")][StringID("A")] = 1.0
+	if w, err := g.ReverseWeight(StringID("S"), StringID("A")); err != nil || w != 100.0 {
+		t.Fatalf("Expected the parent entry 100.000 but %f, %v", w, err)
+	}
+	if _, err := g.ReverseWeight(StringID("S"), StringID("T")); !errors.Is(err, ErrEdgeNotFound) {
+		t.Fatalf("Expected ErrEdgeNotFound but %v", err)
+	}
+	if _, err := g.ReverseWeight(StringID("S"), StringID("X")); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+}
+
+func TestGraph_AddEdges(t *testing.T) {
+	g := NewGraph()
+	a := NewNode("A", make(map[string]string))
+	b := NewNode("B", make(map[string]string))
+	x := NewNode("X", make(map[string]string))
+	g.AddNode(a)
+	g.AddNode(b)
+	errs := g.AddEdges([]Edge{
+		NewEdge(a, b, 1.0, nil),
+		NewEdge(b, x, 2.0, nil),
+		NewEdge(a, b, 3.0, nil),
+	})
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 errors but %v", errs)
+	}
+	if errs[0] != nil || errs[2] != nil {
+		t.Fatalf("Expected nil errors but %v", errs)
+	}
+	if errs[1] == nil {
+		t.Fatal("Expected an error for X but got nil")
+	}
+	if v, err := g.EdgeWeight(StringID("A"), StringID("B")); err != nil || v != 4.0 {
+		t.Fatalf("weight from A to B must be 4.0 but %v %v", v, err)
+	}
+	if err := g.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// benchmarkGraph returns a graph of n nodes with the edges to add,
+// and keeps readers busy on it until stop is called, so that the
+// benchmarks measure insertion under lock contention.
+func benchmarkGraph(n int) (g Graph, edges []Edge, stop func()) {
+	g = NewGraph()
+	nodes := make([]Node, n)
+	for i := range nodes {
+		nodes[i] = NewNode(fmt.Sprintf("%d", i), make(map[string]string))
+		g.AddNode(nodes[i])
+	}
+	edges = make([]Edge, 0, n*4)
+	for i := range nodes {
+		for j := 1; j <= 4; j++ {
+			edges = append(edges, NewEdge(nodes[i], nodes[(i+j)%n], float64(j), nil))
+		}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					g.NodeCount()
+				}
+			}
+		}()
+	}
+	stop = func() {
+		close(done)
+		wg.Wait()
+	}
+	return g, edges, stop
+}
+
+func BenchmarkGraph_AddEdge(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		g, edges, stop := benchmarkGraph(1000)
+		b.StartTimer()
+		for _, edge := range edges {
+			g.AddEdge(edge.Source().ID(), edge.Target().ID(), edge.Weight())
+		}
+		b.StopTimer()
+		stop()
+		b.StartTimer()
+	}
+}
+
+func BenchmarkGraph_AddEdges(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		g, edges, stop := benchmarkGraph(1000)
+		b.StartTimer()
+		g.AddEdges(edges)
+		b.StopTimer()
+		stop()
+		b.StartTimer()
+	}
+}
+
+func TestGraph_Reverse(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	jg, err := NewGraphFromJSON(f, "graph_05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := jg.(*graph)
+	nd, _ := g.Node(StringID("A"))
+	nd.(PropNode).SetProp("color", "red")
+
+	rg := g.Reverse().(*graph)
+	if v, err := rg.EdgeWeight(StringID("F"), StringID("A")); err != nil || v != 1.0 {
+		t.Fatalf("weight from F to A must be 1.0 but %v %v", v, err)
+	}
+	if _, err := rg.EdgeWeight(StringID("A"), StringID("F")); err == nil {
+		t.Fatal("Expected no edge from A to F")
+	}
+	if _, err := g.EdgeWeight(StringID("A"), StringID("F")); err != nil {
+		t.Fatalf("Expected the original graph untouched but %v", err)
+	}
+
+	rrg := rg.Reverse().(*graph)
+	rnd, _ := rg.Node(StringID("A"))
+	rnd.(PropNode).SetProp("color", "blue")
+	if nd.Props()["color"] != "red" {
+		t.Fatalf("Expected props to be copied but %v", nd.Props())
+	}
+	if !reflect.DeepEqual(rrg.nodeChildren, g.nodeChildren) || !reflect.DeepEqual(rrg.nodeParents, g.nodeParents) {
+		t.Fatalf("Expected %s but %s", g, rrg)
+	}
+	if len(rrg.nodes) != len(g.nodes) {
+		t.Fatalf("Expected %d nodes but %d", len(g.nodes), len(rrg.nodes))
+	}
+	for id, nd := range g.nodes {
+		if !reflect.DeepEqual(rrg.nodes[id].Props(), nd.Props()) {
+			t.Fatalf("%s | Expected props %v but %v", id, nd.Props(), rrg.nodes[id].Props())
+		}
+	}
+}
+
+func TestNewGraphFromJSONStream(t *testing.T) {
+	for _, tg := range testgraph.GraphSlice {
+		f, err := os.Open("testdata/graph.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		jg, err := NewGraphFromJSON(f, tg.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g1 := jg.(*graph)
+
+		f2, err := os.Open("testdata/graph.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f2.Close()
+		sg, err := NewGraphFromJSONStream(f2, tg.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g2 := sg.(*graph)
+
+		if g1.NodeCount() != g2.NodeCount() {
+			t.Fatalf("%s | Expected %d nodes but %d", tg.Name, g1.NodeCount(), g2.NodeCount())
+		}
+		for id := range g1.nodes {
+			if _, err := g2.Node(id); err != nil {
+				t.Fatalf("%s | %v", tg.Name, err)
+			}
+		}
+		if !reflect.DeepEqual(g1.nodeChildren, g2.nodeChildren) || !reflect.DeepEqual(g1.nodeParents, g2.nodeParents) {
+			t.Fatalf("%s | Expected %s but %s", tg.Name, g1, g2)
+		}
+	}
+
+	if _, err := NewGraphFromJSONStream(strings.NewReader(`{"graph_00": {}}`), "graph_01"); err == nil {
+		t.Fatal("Expected an error for a missing graph but got nil")
+	}
+	for _, data := range []string{
+		`{"graph_00": {"A": {"B": 1,}}}`,
+		`{"graph_00": {"A": {"B": "x"}}}`,
+		`{"graph_00": {"A": {"B": 1}}`,
+	} {
+		_, err := NewGraphFromJSONStream(strings.NewReader(data), "graph_00")
+		if err == nil || !strings.Contains(err.Error(), "byte offset") {
+			t.Fatalf("Expected a byte offset error for %s but %v", data, err)
+		}
+	}
+}
+
+func TestNewGraphFromJSON_stringWeights(t *testing.T) {
+	numbers := `{"graph_00": {"A": {"B": 14, "C": 2.5}, "B": {"C": -1}}}`
+	mixed := `{"graph_00": {"A": {"B": "14", "C": 2.5}, "B": {"C": " -1 "}}}`
+	for name, load := range map[string]func(io.Reader, string, ...Option) (Graph, error){
+		"NewGraphFromJSON":       NewGraphFromJSON,
+		"NewGraphFromJSONStream": NewGraphFromJSONStream,
+	} {
+		expected, err := load(strings.NewReader(numbers), "graph_00")
+		if err != nil {
+			t.Fatalf("%s | %v", name, err)
+		}
+		g, err := load(strings.NewReader(mixed), "graph_00")
+		if err != nil {
+			t.Fatalf("%s | %v", name, err)
+		}
+		if !Equal(expected, g) {
+			t.Fatalf("%s | Expected %s but %s", name, expected, g)
+		}
+		if w, err := g.EdgeWeight(StringID("A"), StringID("B")); err != nil || w != 14 {
+			t.Fatalf("%s | Expected 14 but %f, %v", name, w, err)
+		}
+
+		for _, data := range []string{
+			`{"graph_00": {"A": {"B": "x"}}}`,
+			`{"graph_00": {"A": {"B": ""}}}`,
+			`{"graph_00": {"A": {"B": "NaN"}}}`,
+			`{"graph_00": {"A": {"B": true}}}`,
+			`{"graph_00": {"A": {"B": null}}}`,
+		} {
+			_, err := load(strings.NewReader(data), "graph_00")
+			if err == nil || !strings.Contains(err.Error(), "weight from A to B must be a number") {
+				t.Fatalf("%s | Expected a weight error for %s but %v", name, data, err)
+			}
+		}
+	}
+}
+
+func TestNewGraphFromJSONStrict(t *testing.T) {
+	for _, tg := range testgraph.GraphSlice {
+		f, err := os.Open("testdata/graph.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		g, err := NewGraphFromJSONStrict(f, tg.Name)
+		if err != nil {
+			t.Fatalf("%s | %v", tg.Name, err)
+		}
+		if g.NodeCount() != tg.TotalNodeCount {
+			t.Fatalf("%s | Expected %d nodes but %d", tg.Name, tg.TotalNodeCount, g.NodeCount())
+		}
+	}
+
+	for _, data := range []string{
+		`{"graph_00": {"A": {"B": 1, "C": 2, "B": 3}}}`,
+		`{"graph_00": {"A": {"B": 1}, "C": {"A": 1}, "A": {"B": 3}}}`,
+		`{"graph_00": {"A": {"B": 1}}, "graph_00": {"A": {"B": 1}}}`,
+	} {
+		_, err := NewGraphFromJSONStrict(strings.NewReader(data), "graph_00")
+		if !errors.Is(err, ErrDuplicateEdge) {
+			t.Fatalf("Expected ErrDuplicateEdge for %s but %v", data, err)
+		}
+		if !strings.Contains(err.Error(), "from A to B") {
+			t.Fatalf("Expected the edge from A to B in the error but %v", err)
+		}
+	}
+
+	data := `{"graph_00": {"A": {"B": 1}, "B": {"A": 1}, "A": {"C": 3}}}`
+	g, err := NewGraphFromJSONStrict(strings.NewReader(data), "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.HasEdge(StringID("A"), StringID("B")) || !g.HasEdge(StringID("A"), StringID("C")) {
+		t.Fatalf("Expected edges from A to B and C but %s", g)
+	}
+}
+
+func TestNewGraphFromYAML_reader(t *testing.T) {
+	data, err := os.ReadFile("testdata/graph.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, rd := range map[string]func() io.Reader{
+		"one byte":       func() io.Reader { return iotest.OneByteReader(bytes.NewReader(data)) },
+		"data with EOF":  func() io.Reader { return iotest.DataErrReader(bytes.NewReader(data)) },
+		"half with EOF":  func() io.Reader { return iotest.DataErrReader(iotest.HalfReader(bytes.NewReader(data))) },
+		"one byte w/EOF": func() io.Reader { return iotest.DataErrReader(iotest.OneByteReader(bytes.NewReader(data))) },
+	} {
+		g, err := NewGraphFromYAML(rd(), "graph_16")
+		if err != nil {
+			t.Fatalf("%s | %v", name, err)
+		}
+		if g.NodeCount() != testgraph.Graph16.TotalNodeCount {
+			t.Fatalf("%s | Expected %d but %d", name, testgraph.Graph16.TotalNodeCount, g.NodeCount())
+		}
+		for _, elem := range testgraph.Graph16.EdgeToWeight {
+			weight, err := g.EdgeWeight(StringID(elem.Nodes[0]), StringID(elem.Nodes[1]))
+			if err != nil {
+				t.Fatalf("%s | %v", name, err)
+			}
+			if weight != elem.Weight {
+				t.Fatalf("%s | Expected %f but %f", name, elem.Weight, weight)
+			}
+		}
+	}
+
+	if _, err := NewGraphFromYAML(iotest.TimeoutReader(bytes.NewReader(data)), "graph_16"); err == nil {
+		t.Fatal("Expected a read error but got nil")
+	}
+}
+
+func TestExportToYAML(t *testing.T) {
+	for _, tg := range testgraph.GraphSlice {
+		f, err := os.Open("testdata/graph.yml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		yg, err := NewGraphFromYAML(f, tg.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g1 := yg.(*graph)
+		g1.AddNode(NewNode("isolated", make(map[string]string)))
+
+		buf1 := new(bytes.Buffer)
+		if err := ExportToYAML(g1, buf1); err != nil {
+			t.Fatal(err)
+		}
+		buf2 := new(bytes.Buffer)
+		if err := ExportToYAML(g1, buf2); err != nil {
+			t.Fatal(err)
+		}
+		if buf1.String() != buf2.String() {
+			t.Fatalf("%s | Expected stable output but\n%s\n%s", tg.Name, buf1, buf2)
+		}
+
+		rg, err := NewGraphFromYAML(buf1, tg.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g2 := rg.(*graph)
+		if g2.NodeCount() != g1.NodeCount() {
+			t.Fatalf("%s | Expected %d nodes but %d", tg.Name, g1.NodeCount(), g2.NodeCount())
+		}
+		if !reflect.DeepEqual(g1.nodeChildren, g2.nodeChildren) || !reflect.DeepEqual(g1.nodeParents, g2.nodeParents) {
+			t.Fatalf("%s | Expected %s but %s", tg.Name, g1, g2)
+		}
+	}
+}
+
+func TestExportToYAML_props(t *testing.T) {
+	g1 := NewGraph()
+	g1.AddNode(NewWeightedNode("A", 3, map[string]string{"k": "v", "n": "42"}))
+	g1.AddNode(NewTypedNode("B", map[string]interface{}{"count": 42, "score": 0.5, "ok": true}))
+	g1.AddEdge(StringID("A"), StringID("B"), 1)
+
+	buf := new(bytes.Buffer)
+	if err := ExportToYAML(g1, buf); err != nil {
+		t.Fatal(err)
+	}
+	g2, err := NewGraphFromYAML(strings.NewReader(buf.String()), g1.ID().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(g1, g2) {
+		t.Fatalf("Expected %s but %s from\n%s", g1, g2, buf)
+	}
+	nd, _ := g2.Node(StringID("A"))
+	if w := nd.(WeightedNode).Weight(); w != 3 {
+		t.Fatalf("Expected weight 3 but %f", w)
+	}
+	if v := nd.Props()["n"]; v != "42" {
+		t.Fatalf("Expected the string 42 but %q", v)
+	}
+	nd, _ = g2.Node(StringID("B"))
+	props := nd.(TypedNode).TypedProps()
+	if props["count"] != 42 || props["score"] != 0.5 || props["ok"] != true {
+		t.Fatalf("Expected typed properties but %#v", props)
+	}
+}
+
+func TestExportToJSON_props(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g1, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, nd := range g1.Nodes() {
+		if len(nd.Props()) != 0 {
+			t.Fatalf("Expected empty props but %v", nd.Props())
+		}
+	}
+	nd, _ := g1.Node(StringID("S"))
+	nd.(PropNode).SetProp("label", "source")
+	nd.(PropNode).SetProp("color", "red")
+	g1.AddNode(NewNode("X", map[string]string{"isolated": "true"}))
+
+	buf := new(bytes.Buffer)
+	if err := ExportToJSON(g1, buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.String()
+
+	for name, load := range map[string]func(io.Reader, string, ...Option) (Graph, error){
+		"NewGraphFromJSON":       NewGraphFromJSON,
+		"NewGraphFromJSONStream": NewGraphFromJSONStream,
+		"NewGraphFromJSONStrict": NewGraphFromJSONStrict,
+	} {
+		g2, err := load(strings.NewReader(data), "graph_00")
+		if err != nil {
+			t.Fatalf("%s | %v", name, err)
+		}
+		if g2.NodeCount() != g1.NodeCount() {
+			t.Fatalf("%s | Expected %d nodes but %d", name, g1.NodeCount(), g2.NodeCount())
+		}
+		for id, nd1 := range g1.Nodes() {
+			nd2, err := g2.Node(id)
+			if err != nil {
+				t.Fatalf("%s | %v", name, err)
+			}
+			if !reflect.DeepEqual(nd1.Props(), nd2.Props()) {
+				t.Fatalf("%s | %s | Expected props %v but %v", name, id, nd1.Props(), nd2.Props())
+			}
+		}
+		if !reflect.DeepEqual(g1.(*graph).nodeChildren, g2.(*graph).nodeChildren) {
+			t.Fatalf("%s | Expected %s but %s", name, g1, g2)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "graph.json")
+	js := g1.ExportToJSON(path)
+	if js["graph_00"]["S"]["A"] != 100.0 {
+		t.Fatalf("weight from S to A must be 100.0 but %v", js)
+	}
+	f2, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f2.Close()
+	g3, err := NewGraphFromJSON(f2, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nd, err := g3.Node(StringID("S")); err != nil || nd.Props()["label"] != "source" {
+		t.Fatalf("Expected props to be saved to disk but %v", err)
+	}
+	if _, ok := js["graph_00"][propsKey]; ok {
+		t.Fatalf("Expected no _props in the returned map but %v", js)
+	}
+
+	g1.AddNode(NewWeightedNode("W", 2, nil))
+	if js := g1.ExportToJSON(path); js["graph_00"][weightsKey]["W"] != 2 {
+		t.Fatalf("Expected the weight of W in the returned map but %v", js)
+	}
+	if js := g1.ExportToJSON(filepath.Join(path, "missing", "graph.json")); js != nil {
+		t.Fatalf("Expected nil for an unwritable path but %v", js)
+	}
+	g1.AddNode(NewNode(propsKey, nil))
+	if js := g1.ExportToJSON(path); js != nil {
+		t.Fatalf("Expected nil for a reserved ID but %v", js)
+	}
+}
+
+func TestGraph_Subgraph(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nd, _ := g.Node(StringID("S"))
+	nd.(PropNode).SetProp("label", "source")
+
+	sg, err := g.Subgraph([]ID{StringID("S"), StringID("A"), StringID("C")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sg.NodeCount() != 3 {
+		t.Fatalf("Expected 3 nodes but %s", sg)
+	}
+	for _, elem := range [][]string{{"S", "A"}, {"S", "C"}, {"A", "S"}, {"C", "S"}} {
+		weight1, err := g.EdgeWeight(StringID(elem[0]), StringID(elem[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		weight2, err := sg.EdgeWeight(StringID(elem[0]), StringID(elem[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if weight1 != weight2 {
+			t.Fatalf("Expected %f but %f", weight1, weight2)
+		}
+	}
+	// A -- 5.000 -→ B crosses the boundary of the subgraph
+	if _, err := sg.EdgeWeight(StringID("A"), StringID("B")); err == nil {
+		t.Fatal("Expected no edge from A to B")
+	}
+	if v, err := sg.ChildNodesOf(StringID("A")); err != nil || len(v) != 1 {
+		t.Fatalf("Expected 1 edge outgoing from A but %v\n\n%s", err, sg)
+	}
+	if snd, _ := sg.Node(StringID("S")); snd.Props()["label"] != "source" {
+		t.Fatalf("Expected props to be copied but %v", snd.Props())
+	}
+	if err := sg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = g.Subgraph([]ID{StringID("S"), StringID("X"), StringID("Y")})
+	if err == nil || !strings.Contains(err.Error(), "X, Y") {
+		t.Fatalf("Expected an error listing X, Y but %v", err)
+	}
+}
+
+func TestGraph_Threshold(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nd, _ := g.Node(StringID("S"))
+	nd.(PropNode).SetProp("label", "source")
+	before := g.String()
+
+	tg := g.Threshold(20)
+	if tg.NodeCount() != g.NodeCount() {
+		t.Fatalf("Expected %d nodes but %s", g.NodeCount(), tg)
+	}
+	kept := 0
+	for _, edge := range g.Edges() {
+		src, tgt := edge.Source().ID(), edge.Target().ID()
+		if edge.Weight() >= 20 {
+			kept++
+			if v, err := tg.EdgeWeight(src, tgt); err != nil || v != edge.Weight() {
+				t.Fatalf("Expected %s to be kept but %v %v", edge, v, err)
+			}
+		} else if tg.HasEdge(src, tgt) {
+			t.Fatalf("Expected %s to be removed", edge)
+		}
+	}
+	if kept == 0 || len(tg.Edges()) != kept {
+		t.Fatalf("Expected %d edges but %s", kept, tg)
+	}
+	if snd, _ := tg.Node(StringID("S")); snd.Props()["label"] != "source" {
+		t.Fatalf("Expected props to be copied but %v", snd.Props())
+	}
+	if g.String() != before {
+		t.Fatalf("Expected the graph to be unchanged but %s", g)
+	}
+
+	// isolated nodes are kept
+	tg = g.Threshold(math.Inf(1))
+	if tg.NodeCount() != g.NodeCount() || len(tg.Edges()) != 0 {
+		t.Fatalf("Expected %d nodes without edges but %s", g.NodeCount(), tg)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	dst := NewGraph()
+	dst.AddNode(NewNode("A", map[string]string{"color": "red", "size": "1"}))
+	dst.AddNode(NewNode("B", make(map[string]string)))
+	dst.AddEdge(StringID("A"), StringID("B"), 1)
+
+	src := NewGraph()
+	src.AddNode(NewNode("C", map[string]string{"color": "blue"}))
+	src.AddNode(NewNode("D", make(map[string]string)))
+	src.AddEdge(StringID("C"), StringID("D"), 2)
+
+	if err := Merge(dst, src, nil); err != nil {
+		t.Fatal(err)
+	}
+	if dst.NodeCount() != 4 {
+		t.Fatalf("Expected 4 nodes but %s", dst)
+	}
+	for _, elem := range []struct {
+		id1, id2 string
+		weight   float64
+	}{{"A", "B", 1}, {"C", "D", 2}} {
+		if v, err := dst.EdgeWeight(StringID(elem.id1), StringID(elem.id2)); err != nil || v != elem.weight {
+			t.Fatalf("weight from %s to %s must be %f but %v %v", elem.id1, elem.id2, elem.weight, v, err)
+		}
+	}
+	if err := dst.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// conflicting edges and props
+	src = NewGraph()
+	src.AddNode(NewNode("A", map[string]string{"color": "green"}))
+	src.AddNode(NewNode("B", make(map[string]string)))
+	src.AddEdge(StringID("A"), StringID("B"), 5)
+
+	if err := Merge(dst, src, nil); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := dst.EdgeWeight(StringID("A"), StringID("B")); v != 1 {
+		t.Fatalf("Expected nil onConflict to keep 1 but %f", v)
+	}
+
+	calls := 0
+	err := Merge(dst, src, func(existing, incoming Edge) float64 {
+		calls++
+		if existing.Source().String() != "A" || incoming.Target().String() != "B" {
+			t.Fatalf("Expected edges from A to B but %s %s", existing, incoming)
+		}
+		return existing.Weight() + incoming.Weight()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("Expected 1 call but %d", calls)
+	}
+	if v, _ := dst.EdgeWeight(StringID("A"), StringID("B")); v != 6 {
+		t.Fatalf("Expected merged weight 6 but %f", v)
+	}
+	nd, _ := dst.Node(StringID("A"))
+	if !reflect.DeepEqual(nd.Props(), map[string]string{"color": "green", "size": "1"}) {
+		t.Fatalf("Expected merged props but %v", nd.Props())
+	}
+
+	err = Merge(Freeze(NewGraph()), src, nil)
+	if !errors.Is(err, ErrFrozen) || errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Expected ErrFrozen but %v", err)
+	}
+	err = Merge(NewGraph(WithMaxNodes(1)), src, nil)
+	if !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Expected ErrCapacityExceeded but %v", err)
+	}
+	err = Merge(rejectingGraph{NewGraph()}, src, nil)
+	if err == nil || errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Expected an error other than ErrCapacityExceeded but %v", err)
+	}
+}
+
+// rejectingGraph is a Graph that does not add any node.
+type rejectingGraph struct {
+	Graph
+}
+
+func (rejectingGraph) AddNode(nd Node) bool {
+	return false
+}
+
+func TestGraph_RenameNode(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_14")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nd, _ := g.Node(StringID("H"))
+	nd.(PropNode).SetProp("label", "sink")
+
+	if err := g.RenameNode(StringID("D"), StringID("X")); err != nil {
+		t.Fatal(err)
+	}
+	// H has a self-loop
+	if err := g.RenameNode(StringID("H"), StringID("Y")); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, elem := range [][]string{{"C", "X"}, {"X", "C"}, {"X", "Y"}, {"G", "Y"}, {"Y", "Y"}} {
+		if v, err := g.EdgeWeight(StringID(elem[0]), StringID(elem[1])); err != nil || v != 1.0 {
+			t.Fatalf("weight from %s to %s must be 1.0 but %v %v", elem[0], elem[1], v, err)
+		}
+	}
+	for _, id := range []string{"D", "H"} {
+		if _, err := g.Node(StringID(id)); err == nil {
+			t.Fatalf("Expected %s to be renamed", id)
+		}
+	}
+	if v, err := g.ChildNodesOf(StringID("C")); err != nil || len(v) != 2 {
+		t.Fatalf("Expected 2 edges outgoing from C but %v\n\n%s", err, g)
+	}
+	if _, ok := g.(*graph).nodeParents[StringID("C")][StringID("D")]; ok {
+		t.Fatalf("Expected no edge from D to C\n\n%s", g)
+	}
+	if nd, _ := g.Node(StringID("Y")); nd.Props()["label"] != "sink" || nd.ID() != StringID("Y") {
+		t.Fatalf("Expected Y to keep props but %v", nd.Props())
+	}
+
+	if err := g.RenameNode(StringID("D"), StringID("Z")); err == nil {
+		t.Fatal("Expected an error renaming a missing node")
+	}
+	if err := g.RenameNode(StringID("A"), StringID("B")); err == nil {
+		t.Fatal("Expected an error renaming to an existing node")
+	}
+}
+
+func TestGraph_SelfLoops(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_14")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.AddEdge(StringID("B"), StringID("B"), 1)
+	if rs := g.SelfLoops(); !reflect.DeepEqual(rs, []ID{StringID("B"), StringID("H")}) {
+		t.Fatalf("Expected [B H] but %v", rs)
+	}
+	g.DeleteEdge(StringID("B"), StringID("B"))
+	if rs := g.SelfLoops(); !reflect.DeepEqual(rs, []ID{StringID("H")}) {
+		t.Fatalf("Expected [H] but %v", rs)
+	}
+}
+
+func TestNewGraphNoSelfLoops(t *testing.T) {
+	g := NewGraphNoSelfLoops()
+	g.AddNode(NewNode("A", make(map[string]string)))
+	g.AddNode(NewNode("B", make(map[string]string)))
+	if err := g.AddEdge(StringID("A"), StringID("A"), 1); err != ErrSelfLoop {
+		t.Fatalf("Expected ErrSelfLoop but %v", err)
+	}
+	if err := g.ReplaceEdge(StringID("B"), StringID("B"), 1); err != ErrSelfLoop {
+		t.Fatalf("Expected ErrSelfLoop but %v", err)
+	}
+	if err := g.AddEdge(StringID("A"), StringID("B"), 1); err != nil {
+		t.Fatal(err)
+	}
+	if rs := g.SelfLoops(); len(rs) != 0 {
+		t.Fatalf("Expected no self-loops but %v", rs)
+	}
+	if err := g.Reverse().AddEdge(StringID("A"), StringID("A"), 1); err != ErrSelfLoop {
+		t.Fatalf("Expected ErrSelfLoop on the reversed graph but %v", err)
+	}
+}
+
+func TestGraph_String(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `A -- 1.000 -→ F
+B -- 1.000 -→ A
+D -- 1.000 -→ B
+D -- 1.000 -→ C
+E -- 1.000 -→ C
+E -- 1.000 -→ F
+`
+	for i := 0; i < 10; i++ {
+		if s := g.String(); s != expected {
+			t.Fatalf("Expected\n%s\nbut\n%s", expected, s)
+		}
+	}
+}
+
+func TestGraph_HasNode_HasEdge(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.HasNode(StringID("A")) {
+		t.Fatal("Expected A to exist")
+	}
+	if g.HasNode(StringID("X")) {
+		t.Fatal("Expected X not to exist")
+	}
+	if !g.HasEdge(StringID("A"), StringID("F")) {
+		t.Fatal("Expected an edge from A to F")
+	}
+	// the reverse direction, existing nodes without an edge,
+	// and missing nodes
+	for _, elem := range [][]string{{"F", "A"}, {"A", "B"}, {"A", "X"}, {"X", "A"}, {"X", "Y"}} {
+		if g.HasEdge(StringID(elem[0]), StringID(elem[1])) {
+			t.Fatalf("Expected no edge from %s to %s", elem[0], elem[1])
+		}
+	}
+	g.DeleteEdge(StringID("A"), StringID("F"))
+	if g.HasEdge(StringID("A"), StringID("F")) {
+		t.Fatal("Expected the edge from A to F to be deleted")
+	}
+}
+
+func TestGraph_Nodes(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := g.Nodes()
+	delete(nodes, StringID("A"))
+	nodes[StringID("X")] = NewNode("X", make(map[string]string))
+	if g.NodeCount() != 6 || !g.HasNode(StringID("A")) || g.HasNode(StringID("X")) {
+		t.Fatalf("Expected the graph untouched but %v", g.Nodes())
+	}
+}
+
+func TestGraph_EachNode(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	visited := make(map[ID]int)
+	g.EachNode(func(nd Node) bool {
+		visited[nd.ID()]++
+		return true
+	})
+	if len(visited) != 6 {
+		t.Fatalf("Expected 6 nodes but %v", visited)
+	}
+	for id, cnt := range visited {
+		if cnt != 1 {
+			t.Fatalf("Expected %s to be visited once but %d", id, cnt)
+		}
+	}
+
+	cnt := 0
+	g.EachNode(func(nd Node) bool {
+		cnt++
+		return cnt < 2
+	})
+	if cnt != 2 {
+		t.Fatalf("Expected to stop after 2 nodes but %d", cnt)
+	}
+}
+
+func TestGraph_EachEdge(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	edges := g.Edges()
+	weights := make(map[[2]ID]float64)
+	for _, edge := range edges {
+		weights[[2]ID{edge.Source().ID(), edge.Target().ID()}] = edge.Weight()
+	}
+
+	visited := make(map[[2]ID]int)
+	g.EachEdge(func(src, tgt ID, weight float64) bool {
+		if v, ok := weights[[2]ID{src, tgt}]; !ok || v != weight {
+			t.Errorf("Expected weight %v from %s to %s but %f", v, src, tgt, weight)
+		}
+		visited[[2]ID{src, tgt}]++
+		return true
+	})
+	if len(visited) != len(edges) {
+		t.Fatalf("Expected %d edges but %v", len(edges), visited)
+	}
+	for key, cnt := range visited {
+		if cnt != 1 {
+			t.Fatalf("Expected %s to %s to be visited once but %d", key[0], key[1], cnt)
+		}
+	}
+
+	cnt := 0
+	g.EachEdge(func(src, tgt ID, weight float64) bool {
+		cnt++
+		return cnt < 3
+	})
+	if cnt != 3 {
+		t.Fatalf("Expected to stop after 3 edges but %d", cnt)
+	}
+}
+
+func TestGraph_DeleteNodes(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	jg, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []ID{StringID("A"), StringID("D"), StringID("X"), StringID("A")}
+	if n := jg.DeleteNodes(ids); n != 2 {
+		t.Fatalf("Expected 2 nodes deleted but %d", n)
+	}
+	if jg.NodeCount() != 6 {
+		t.Fatalf("Expected 6 nodes but %d", jg.NodeCount())
+	}
+	if err := jg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	g := jg.(*graph)
+	for _, id := range ids {
+		if _, ok := g.nodeChildren[id]; ok {
+			t.Fatalf("Expected no children of %s", id)
+		}
+		if _, ok := g.nodeParents[id]; ok {
+			t.Fatalf("Expected no parents of %s", id)
+		}
+		for id2, smap := range g.nodeChildren {
+			if _, ok := smap[id]; ok {
+				t.Fatalf("Expected no edge from %s to %s", id2, id)
+			}
+		}
+		for id2, smap := range g.nodeParents {
+			if _, ok := smap[id]; ok {
+				t.Fatalf("Expected no edge from %s to %s", id, id2)
+			}
+		}
+	}
+	if n := jg.DeleteNodes(ids); n != 0 {
+		t.Fatalf("Expected 0 nodes deleted but %d", n)
+	}
+}
+
+func TestGraph_DeleteEdgesWhere(t *testing.T) {
+	for _, tg := range testgraph.GraphSlice {
+		f, err := os.Open("testdata/graph.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		g, err := NewGraphFromJSON(f, tg.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := 0
+		for _, edge := range g.Edges() {
+			if edge.Weight() < 10 {
+				expected++
+			}
+		}
+		total := len(g.Edges())
+
+		var events int
+		g.Subscribe(func(ev GraphEvent) {
+			if ev.Type == EdgeDeleted {
+				events++
+			}
+		})
+		n := g.DeleteEdgesWhere(func(src, tgt ID, weight float64) bool { return weight < 10 })
+		if n != expected || events != expected {
+			t.Fatalf("%s | Expected %d deleted edges but %d with %d events", tg.Name, expected, n, events)
+		}
+		edges := g.Edges()
+		if len(edges) != total-expected {
+			t.Fatalf("%s | Expected %d edges but %d", tg.Name, total-expected, len(edges))
+		}
+		for _, edge := range edges {
+			if edge.Weight() < 10 {
+				t.Fatalf("%s | Expected no edge under 10 but %s", tg.Name, edge)
+			}
+		}
+		if err := g.Validate(); err != nil {
+			t.Fatalf("%s | Expected a valid graph but %v", tg.Name, err)
+		}
+		if cnt := g.(*graph).edgeCount; cnt != total-expected {
+			t.Fatalf("%s | Expected an edge count of %d but %d", tg.Name, total-expected, cnt)
+		}
+	}
+}
+
+func TestGraph_MapWeights(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	jg, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := jg.(*graph)
+	before := make(map[ID]map[ID]float64)
+	for id1, tmap := range g.nodeChildren {
+		before[id1] = make(map[ID]float64)
+		for id2, weight := range tmap {
+			before[id1][id2] = weight
+		}
+	}
+
+	g.MapWeights(func(src, tgt ID, w float64) float64 { return 2 * w })
+
+	for id1, tmap := range before {
+		for id2, weight := range tmap {
+			if v := g.nodeChildren[id1][id2]; v != 2*weight {
+				t.Fatalf("Expected child weight %f from %s to %s but %f", 2*weight, id1, id2, v)
+			}
+			if v := g.nodeParents[id2][id1]; v != 2*weight {
+				t.Fatalf("Expected parent weight %f from %s to %s but %f", 2*weight, id1, id2, v)
+			}
+		}
+	}
+	if err := g.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGraph_FindNodesByProp(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(NewNode("C", map[string]string{"type": "gene"}))
+	g.AddNode(NewNode("A", map[string]string{"type": "gene"}))
+	g.AddNode(NewNode("B", map[string]string{"type": "protein"}))
+	g.AddNode(NewNode("D", nil))
+
+	ids := func(nds []Node) []ID {
+		rs := []ID{}
+		for _, nd := range nds {
+			rs = append(rs, nd.ID())
+		}
+		return rs
+	}
+	if rs := ids(g.FindNodesByProp("type", "gene")); !reflect.DeepEqual(rs, []ID{StringID("A"), StringID("C")}) {
+		t.Fatalf("Expected [A C] but %v", rs)
+	}
+	if rs := g.FindNodesByProp("type", ""); len(rs) != 0 {
+		t.Fatalf("Expected no nodes but %v", ids(rs))
+	}
+
+	g.DeleteNode(StringID("A"))
+	nd, _ := g.Node(StringID("B"))
+	nd.(PropNode).SetProp("type", "gene")
+	if rs := ids(g.FindNodesByProp("type", "gene")); !reflect.DeepEqual(rs, []ID{StringID("B"), StringID("C")}) {
+		t.Fatalf("Expected [B C] but %v", rs)
+	}
+	if rs := g.FindNodesByProp("type", "protein"); len(rs) != 0 {
+		t.Fatalf("Expected no nodes but %v", ids(rs))
+	}
+}
+
+func TestGraph_Capacity(t *testing.T) {
+	g := NewGraph(WithMaxNodes(3), WithMaxEdges(2))
+	if !g.AddNode(NewNode("A", nil)) || !g.AddNode(NewNode("B", nil)) {
+		t.Fatal("Expected nodes to be added")
+	}
+	if n := g.AddNodesFromIDs([]string{"A", "C", "D"}); n != 1 {
+		t.Fatalf("Expected 1 node to be added but %d", n)
+	}
+	if g.AddNode(NewNode("E", nil)) {
+		t.Fatal("Expected AddNode to fail past the capacity")
+	}
+	if g.NodeCount() != 3 || g.HasNode(StringID("D")) || g.HasNode(StringID("E")) {
+		t.Fatalf("Expected [A B C] but %v", sortedIDs(g.Nodes()))
+	}
+
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.ReplaceEdge(StringID("B"), StringID("C"), 1)
+	if err := g.AddEdge(StringID("A"), StringID("C"), 1); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Expected ErrCapacityExceeded but %v", err)
+	}
+	if err := g.ReplaceEdge(StringID("C"), StringID("A"), 1); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Expected ErrCapacityExceeded but %v", err)
+	}
+	if g.HasEdge(StringID("A"), StringID("C")) || g.HasEdge(StringID("C"), StringID("A")) {
+		t.Fatalf("Expected the graph to be unchanged but %s", g)
+	}
+
+	// existing edges can still be updated
+	if err := g.AddEdge(StringID("A"), StringID("B"), 1); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := g.EdgeWeight(StringID("A"), StringID("B")); v != 2 {
+		t.Fatalf("Expected weight 2 but %f", v)
+	}
+
+	// deleting frees capacity
+	g.DeleteEdge(StringID("A"), StringID("B"))
+	if err := g.AddEdge(StringID("C"), StringID("A"), 1); err != nil {
+		t.Fatal(err)
+	}
+	g.DeleteNodes([]ID{StringID("A"), StringID("C")})
+	if g.(*graph).edgeCount != 0 {
+		t.Fatalf("Expected no edges but %d", g.(*graph).edgeCount)
+	}
+	if !g.AddNode(NewNode("D", nil)) {
+		t.Fatal("Expected node to be added after deleting")
+	}
+
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := NewGraphFromJSON(f, "graph_00", WithMaxNodes(3)); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Expected ErrCapacityExceeded but %v", err)
+	}
+	f.Seek(0, io.SeekStart)
+	if _, err := NewGraphFromJSONStream(f, "graph_00", WithMaxEdges(3)); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Expected ErrCapacityExceeded but %v", err)
+	}
+	if _, err := NewGraphFromCSV(strings.NewReader("A,B\nB,C\nC,A\n"), "g", WithMaxEdges(2)); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Expected ErrCapacityExceeded but %v", err)
+	}
+	if _, err := NewGraphFromYAML(strings.NewReader("g:\n  A:\n    B: 1\n    C: 1\n"), "g", WithMaxNodes(2)); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Expected ErrCapacityExceeded but %v", err)
+	}
+}
+
+func TestGraph_StreamJSON(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	g := NewGraph()
+	for i := 0; i < 500; i++ {
+		id := fmt.Sprintf("n%d", i)
+		switch i % 7 {
+		case 0:
+			g.AddNode(NewNode(id, map[string]string{"label": "<a & b>", "i": fmt.Sprint(i)}))
+		case 1:
+			g.AddNode(NewTypedNode(id, map[string]interface{}{"score": rnd.Float64(), "rank": i}))
+		default:
+			g.AddNode(NewNode(id, nil))
+		}
+	}
+	g.AddNode(NewNode("é\"quoted\"", nil))
+	ids := sortedIDs(g.Nodes())
+	for i := 0; i < 3000; i++ {
+		id1, id2 := ids[rnd.Intn(len(ids))], ids[rnd.Intn(len(ids))]
+		g.ReplaceEdge(id1, id2, rnd.NormFloat64()*1e3)
+	}
+
+	check := func(g Graph) {
+		buf1 := new(bytes.Buffer)
+		if err := ExportToJSON(g, buf1); err != nil {
+			t.Fatal(err)
+		}
+		buf2 := new(bytes.Buffer)
+		if err := g.StreamJSON(buf2); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf1.Bytes(), buf2.Bytes()) {
+			t.Fatalf("Expected\n%s\nbut\n%s", buf1, buf2)
+		}
+	}
+	check(g)
+	check(NewGraph())
+
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	jg, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(jg)
+}
+
+func TestReservedIDs(t *testing.T) {
+	for _, key := range []string{propsKey, weightsKey} {
+		g := NewGraph()
+		g.AddNode(NewNode(key, nil))
+		g.AddNode(NewNode("A", map[string]string{"label": "a"}))
+		g.AddEdge(StringID(key), StringID("A"), 7)
+
+		if err := ExportToJSON(g, new(bytes.Buffer)); !errors.Is(err, ErrReservedID) {
+			t.Fatalf("%s | Expected ErrReservedID from ExportToJSON but %v", key, err)
+		}
+		if err := g.StreamJSON(new(bytes.Buffer)); !errors.Is(err, ErrReservedID) {
+			t.Fatalf("%s | Expected ErrReservedID from StreamJSON but %v", key, err)
+		}
+		if err := ExportToYAML(g, new(bytes.Buffer)); !errors.Is(err, ErrReservedID) {
+			t.Fatalf("%s | Expected ErrReservedID from ExportToYAML but %v", key, err)
+		}
+
+		for _, data := range []string{
+			fmt.Sprintf(`{"g": {"A": {%q: 7}}}`, key),
+			fmt.Sprintf(`{"g": {"_props": {%q: {"x": "y"}}}}`, key),
+			fmt.Sprintf(`{"g": {"_weights": {%q: 2}}}`, key),
+		} {
+			for name, load := range map[string]func(io.Reader, string, ...Option) (Graph, error){
+				"NewGraphFromJSON":       NewGraphFromJSON,
+				"NewGraphFromJSONStream": NewGraphFromJSONStream,
+			} {
+				if _, err := load(strings.NewReader(data), "g"); !errors.Is(err, ErrReservedID) {
+					t.Fatalf("%s | Expected ErrReservedID for %s but %v", name, data, err)
+				}
+			}
+		}
+		data := fmt.Sprintf("g:\n  A:\n    %s: 7\n", key)
+		if _, err := NewGraphFromYAML(strings.NewReader(data), "g"); !errors.Is(err, ErrReservedID) {
+			t.Fatalf("NewGraphFromYAML | Expected ErrReservedID for %s but %v", data, err)
+		}
+	}
+}
+
+func TestWithIDValidator(t *testing.T) {
+	errBadID := errors.New("ID must not be empty or contain white space")
+	validate := func(id string) error {
+		if id == "" || strings.IndexFunc(id, unicode.IsSpace) >= 0 {
+			return errBadID
+		}
+		return nil
+	}
+
+	for name, data := range map[string]string{
+		"empty source": `{"g": {"": {"A": 1}}}`,
+		"empty target": `{"g": {"A": {"": 1}}}`,
+		"white space":  `{"g": {"A": {"B C": 1}}}`,
+		"props":        `{"g": {"_props": {" A": {"x": "y"}}}}`,
+		"after valid":  `{"h": {"A": {"B": 1}}, "g": {"A": {"B": 1}, "B": {"C D": 1}}}`,
+	} {
+		_, err := NewGraphFromJSON(strings.NewReader(data), "g", WithIDValidator(validate))
+		if !errors.Is(err, errBadID) {
+			t.Fatalf("%s: Expected errBadID but %v", name, err)
+		}
+		_, err = NewGraphFromJSONStream(strings.NewReader(data), "g", WithIDValidator(validate))
+		if !errors.Is(err, errBadID) {
+			t.Fatalf("%s: Expected errBadID but %v", name, err)
+		}
+	}
+	if _, err := NewGraphFromYAML(strings.NewReader("g:\n  A:\n    \"B C\": 1\n"), "g", WithIDValidator(validate)); err == nil || !strings.Contains(err.Error(), `"B C"`) {
+		t.Fatalf("Expected an error naming \"B C\" but %v", err)
+	}
+
+	// with several bad IDs, the first one in sorted order is reported
+	data := `{"g": {"Z": {"z z": 1}, "M": {"m m": 1}, "A": {"a a": 1, "b b": 1}}}`
+	ydata := "g:\n  Z:\n    z z: 1\n  M:\n    m m: 1\n  A:\n    b b: 1\n    a a: 1\n"
+	for i := 0; i < 20; i++ {
+		if _, err := NewGraphFromJSON(strings.NewReader(data), "g", WithIDValidator(validate)); err == nil || !strings.Contains(err.Error(), `"a a"`) {
+			t.Fatalf("Expected an error naming \"a a\" but %v", err)
+		}
+		if _, err := NewGraphFromYAML(strings.NewReader(ydata), "g", WithIDValidator(validate)); err == nil || !strings.Contains(err.Error(), `"a a"`) {
+			t.Fatalf("Expected an error naming \"a a\" but %v", err)
+		}
+	}
+
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00", WithIDValidator(validate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NodeCount() != 8 {
+		t.Fatalf("Expected 8 nodes but %s", g)
+	}
+	g, err = NewGraphFromYAML(strings.NewReader("g:\n  A:\n    B: 1\n"), "g", WithIDValidator(validate))
+	if err != nil || g.NodeCount() != 2 {
+		t.Fatalf("Expected 2 nodes but %v %v", g, err)
+	}
+}
+
+func TestGraph_WeightStats(t *testing.T) {
+	g := NewGraph()
+	if min, max, mean, stddev, count := g.WeightStats(); min != 0 || max != 0 || mean != 0 || stddev != 0 || count != 0 {
+		t.Fatalf("Expected zeros but %f %f %f %f %d", min, max, mean, stddev, count)
+	}
+
+	for _, id := range []string{"A", "B", "C", "D"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	edges := []struct {
+		src, tgt string
+		weight   float64
+	}{
+		{"A", "B", 2}, {"A", "C", 4}, {"A", "D", 4}, {"B", "C", 4},
+		{"B", "D", 5}, {"C", "D", 5}, {"D", "A", 7}, {"C", "A", 9},
+	}
+	for _, e := range edges {
+		if err := g.AddEdge(StringID(e.src), StringID(e.tgt), e.weight); err != nil {
+			t.Fatal(err)
+		}
+	}
+	min, max, mean, stddev, count := g.WeightStats()
+	if min != 2 || max != 9 || count != 8 {
+		t.Fatalf("Expected min 2, max 9 and count 8 but %f %f %d", min, max, count)
+	}
+	if math.Abs(mean-5) > EqualEpsilon || math.Abs(stddev-2) > EqualEpsilon {
+		t.Fatalf("Expected mean 5 and stddev 2 but %f %f", mean, stddev)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	build := func() Graph {
+		g := NewGraph()
+		g.AddNode(NewNode("A", map[string]string{"color": "red"}))
+		g.AddNode(NewNode("B", nil))
+		g.AddNode(NewNode("C", nil))
+		g.AddEdge(StringID("A"), StringID("B"), 1)
+		g.AddEdge(StringID("B"), StringID("C"), 2)
+		return g
+	}
+	a := build()
+	if !Equal(a, build()) {
+		t.Fatal("Expected equal graphs")
+	}
+	if !Equal(a, a.Reverse().Reverse()) {
+		t.Fatal("Expected equal graphs after reversing twice")
+	}
+
+	b := build()
+	b.ReplaceEdge(StringID("B"), StringID("C"), 2+EqualEpsilon/2)
+	if !Equal(a, b) {
+		t.Fatal("Expected equal graphs within epsilon")
+	}
+
+	b = build()
+	b.ReplaceEdge(StringID("B"), StringID("C"), 2.5)
+	if Equal(a, b) {
+		t.Fatal("Expected different graphs by edge weight")
+	}
+
+	b = build()
+	b.AddEdge(StringID("C"), StringID("A"), 1)
+	if Equal(a, b) || Equal(b, a) {
+		t.Fatal("Expected different graphs by extra edge")
+	}
+
+	b = build()
+	b.AddNode(NewNode("D", nil))
+	if Equal(a, b) || Equal(b, a) {
+		t.Fatal("Expected different graphs by extra node")
+	}
+
+	b = build()
+	nd, _ := b.Node(StringID("A"))
+	nd.(PropNode).SetProp("color", "blue")
+	if Equal(a, b) {
+		t.Fatal("Expected different graphs by node property")
+	}
+}
+
+func TestNewTypedNode(t *testing.T) {
+	nd := NewTypedNode("A", map[string]interface{}{"n": 42, "f": 1.5, "ok": true, "name": "a"})
+	expected := map[string]string{"n": "42", "f": "1.5", "ok": "true", "name": "a"}
+	if !reflect.DeepEqual(nd.Props(), expected) {
+		t.Fatalf("Expected %v but %v", expected, nd.Props())
+	}
+
+	g := NewGraph()
+	g.AddNode(nd)
+	g.AddNode(NewNode("B", map[string]string{"label": "b"}))
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+
+	buf := new(bytes.Buffer)
+	if err := ExportToJSON(g, buf); err != nil {
+		t.Fatal(err)
+	}
+	for name, load := range map[string]func(io.Reader, string, ...Option) (Graph, error){
+		"NewGraphFromJSON":       NewGraphFromJSON,
+		"NewGraphFromJSONStream": NewGraphFromJSONStream,
+	} {
+		g2, err := load(bytes.NewReader(buf.Bytes()), "")
+		if err != nil {
+			t.Fatalf("%s | %v", name, err)
+		}
+		nd2, err := g2.Node(StringID("A"))
+		if err != nil {
+			t.Fatalf("%s | %v", name, err)
+		}
+		props := nd2.(TypedNode).TypedProps()
+		if n, ok := props["n"].(int); !ok || n != 42 {
+			t.Fatalf("%s | Expected int 42 but %#v", name, props["n"])
+		}
+		if !reflect.DeepEqual(props, nd.TypedProps()) {
+			t.Fatalf("%s | Expected %v but %v", name, nd.TypedProps(), props)
+		}
+		if !Equal(g, g2) {
+			t.Fatalf("%s | Expected equal graphs", name)
+		}
+	}
+}
+
+func TestNewGraphFromYAML_props(t *testing.T) {
+	data := `graph_00:
+  _props:
+    A:
+      count: 42
+      label: a
+  A:
+    B: 1.5
+`
+	g, err := NewGraphFromYAML(strings.NewReader(data), "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nd, err := g.Node(StringID("A"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := nd.(TypedNode).TypedProps()
+	if n, ok := props["count"].(int); !ok || n != 42 || props["label"] != "a" {
+		t.Fatalf("Expected count 42 and label a but %#v", props)
+	}
+	if nd.Props()["count"] != "42" {
+		t.Fatalf("Expected string 42 but %q", nd.Props()["count"])
+	}
+	if v, err := g.EdgeWeight(StringID("A"), StringID("B")); err != nil || v != 1.5 {
+		t.Fatalf("Expected weight 1.5 but %v %v", v, err)
+	}
+}
+
+func TestNewWeightedNode(t *testing.T) {
+	g1 := NewGraph()
+	g1.AddNode(NewWeightedNode("A", 2.5, map[string]string{"label": "a"}))
+	g1.AddNode(NewWeightedNode("B", -1, nil))
+	g1.AddNode(NewNode("C", nil))
+	g1.AddEdge(StringID("A"), StringID("B"), 1)
+	g1.AddEdge(StringID("B"), StringID("C"), 2)
+
+	expected := map[ID]float64{StringID("A"): 2.5, StringID("B"): -1, StringID("C"): 0}
+	check := func(name string, g Graph) {
+		for id, w := range expected {
+			nd, err := g.Node(id)
+			if err != nil {
+				t.Fatalf("%s | %v", name, err)
+			}
+			wn, ok := nd.(WeightedNode)
+			if !ok {
+				t.Fatalf("%s | Expected a WeightedNode but %T", name, nd)
+			}
+			if wn.Weight() != w {
+				t.Fatalf("%s | Expected weight %f for %s but %f", name, w, id, wn.Weight())
+			}
+		}
+		if !Equal(g1, g) {
+			t.Fatalf("%s | Expected %s but %s", name, g1, g)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := ExportToJSON(g1, buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.String()
+	if !strings.Contains(data, `"_weights":{"A":2.5,"B":-1}`) {
+		t.Fatalf("Expected the weights of A and B but %s", data)
+	}
+	sbuf := new(bytes.Buffer)
+	if err := g1.StreamJSON(sbuf); err != nil {
+		t.Fatal(err)
+	}
+	if sbuf.String() != data {
+		t.Fatalf("Expected %s but %s", data, sbuf.String())
+	}
+	for name, load := range map[string]func(io.Reader, string, ...Option) (Graph, error){
+		"NewGraphFromJSON":       NewGraphFromJSON,
+		"NewGraphFromJSONStream": NewGraphFromJSONStream,
+	} {
+		g2, err := load(strings.NewReader(data), g1.ID().String())
+		if err != nil {
+			t.Fatalf("%s | %v", name, err)
+		}
+		check(name, g2)
+	}
+
+	enc, err := g1.(*graph).GobEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g3 := newGraph()
+	if err := g3.GobDecode(enc); err != nil {
+		t.Fatal(err)
+	}
+	check("gob", g3)
+	check("Reverse", g1.Reverse().Reverse())
+
+	ybuf := new(bytes.Buffer)
+	if err := ExportToYAML(g1, ybuf); err != nil {
+		t.Fatal(err)
+	}
+	g4, err := NewGraphFromYAML(ybuf, g1.ID().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("ExportToYAML", g4)
+
+	yg, err := NewGraphFromYAML(strings.NewReader(`graph_00:
+  _props:
+    A:
+      label: a
+  _weights:
+    A: 2.5
+    B: -1
+  A:
+    B: 1
+  B:
+    C: 2
+`), "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("NewGraphFromYAML", yg)
+
+	if _, err := NewGraphFromJSON(strings.NewReader(`{"g": {"_weights": {"A": "x"}}}`), "g"); err == nil || !strings.Contains(err.Error(), "weight of A") {
+		t.Fatalf("Expected a weight error but %v", err)
+	}
+}
+
+func TestGraph_NodesSorted(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := func(nds []Node) string {
+		ss := []string{}
+		for _, nd := range nds {
+			ss = append(ss, nd.ID().String())
+		}
+		return strings.Join(ss, " ")
+	}
+
+	first := names(g.NodesSorted())
+	if first != "A B C D E F S T" {
+		t.Fatalf("Expected A B C D E F S T but %s", first)
+	}
+	for i := 0; i < 10; i++ {
+		if s := names(g.NodesSorted()); s != first {
+			t.Fatalf("Expected %s but %s", first, s)
+		}
+	}
+	pnds, err := g.ParentNodesSorted(StringID("D"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := names(pnds); s != "A B E F T" {
+		t.Fatalf("Expected A B E F T but %s", s)
+	}
+	cnds, err := g.ChildNodesSorted(StringID("S"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := names(cnds); s != "A B C" {
+		t.Fatalf("Expected A B C but %s", s)
+	}
+	if _, err := g.ChildNodesSorted(StringID("X")); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+	if _, err := g.ParentNodesSorted(StringID("X")); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+
+	// by the string value, so 10 comes before 9
+	ig := NewGraph()
+	for _, i := range []int{9, 10, 100, 2} {
+		ig.AddNode(NewNodeWithID(IntID(i), nil))
+	}
+	for _, i := range []int{9, 10, 100, 2} {
+		ig.AddEdge(IntID(2), IntID(i), 1)
+	}
+	if s := names(ig.NodesSorted()); s != "10 100 2 9" {
+		t.Fatalf("Expected 10 100 2 9 but %s", s)
+	}
+	cnds, err = ig.ChildNodesSorted(IntID(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := names(cnds); s != "10 100 2 9" {
+		t.Fatalf("Expected 10 100 2 9 but %s", s)
+	}
+}
+
+func TestGraph_ChildNodesFiltered(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	above := func(threshold float64) func(Node, float64) bool {
+		return func(n Node, weight float64) bool { return weight > threshold }
+	}
+
+	cmap, err := g.ChildNodesFiltered(StringID("S"), above(50))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmap) != 2 || cmap[StringID("A")] == nil || cmap[StringID("C")] == nil {
+		t.Fatalf("Expected children A and C but %v", cmap)
+	}
+
+	pmap, err := g.ParentNodesFiltered(StringID("T"), above(18))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pmap) != 2 || pmap[StringID("A")] == nil || pmap[StringID("E")] == nil {
+		t.Fatalf("Expected parents A and E but %v", pmap)
+	}
+
+	if _, err := g.ChildNodesFiltered(StringID("X"), above(0)); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+	if _, err := g.ParentNodesFiltered(StringID("X"), above(0)); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+}
+
+func TestGraph_SetEdgeMergeFunc(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(NewNode("A", nil))
+	g.AddNode(NewNode("B", nil))
+
+	g.AddEdge(StringID("A"), StringID("B"), 3)
+	g.AddEdge(StringID("A"), StringID("B"), 2)
+	if v, _ := g.EdgeWeight(StringID("A"), StringID("B")); v != 5 {
+		t.Fatalf("Expected the summed weight 5 but %f", v)
+	}
+
+	g.SetEdgeMergeFunc(math.Max)
+	g.AddEdge(StringID("A"), StringID("B"), 4)
+	if v, _ := g.EdgeWeight(StringID("A"), StringID("B")); v != 5 {
+		t.Fatalf("Expected the larger weight 5 but %f", v)
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 7)
+	if v, _ := g.EdgeWeight(StringID("A"), StringID("B")); v != 7 {
+		t.Fatalf("Expected the larger weight 7 but %f", v)
+	}
+	if err := g.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	g.SetEdgeMergeFunc(nil)
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	if v, _ := g.EdgeWeight(StringID("A"), StringID("B")); v != 8 {
+		t.Fatalf("Expected the summed weight 8 but %f", v)
+	}
+}
+
+func TestGraph_SourcesSinks(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_06")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs := g.Sources(); !reflect.DeepEqual(rs, []ID{StringID("A"), StringID("B"), StringID("C")}) {
+		t.Fatalf("Expected [A B C] but %v", rs)
+	}
+	if rs := g.Sinks(); !reflect.DeepEqual(rs, []ID{StringID("F"), StringID("G"), StringID("H")}) {
+		t.Fatalf("Expected [F G H] but %v", rs)
+	}
+
+	g.AddNode(NewNode("X", nil))
+	if rs := g.Sources(); !reflect.DeepEqual(rs, []ID{StringID("A"), StringID("B"), StringID("C"), StringID("X")}) {
+		t.Fatalf("Expected [A B C X] but %v", rs)
+	}
+	if rs := g.Sinks(); !reflect.DeepEqual(rs, []ID{StringID("F"), StringID("G"), StringID("H"), StringID("X")}) {
+		t.Fatalf("Expected [F G H X] but %v", rs)
+	}
+}
+
+func TestGraph_AddNodesFromIDs(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(NewNode("A", map[string]string{"color": "red"}))
+	if n := g.AddNodesFromIDs([]string{"A", "B", "C", "B"}); n != 2 {
+		t.Fatalf("Expected 2 nodes added but %d", n)
+	}
+	if g.NodeCount() != 3 {
+		t.Fatalf("Expected 3 nodes but %d", g.NodeCount())
+	}
+	nd, err := g.Node(StringID("A"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nd.Props()["color"] != "red" {
+		t.Fatalf("Expected the existing node A to be kept but %v", nd.Props())
+	}
+	if n := g.AddNodesFromIDs(nil); n != 0 {
+		t.Fatalf("Expected 0 nodes added but %d", n)
+	}
+}
+
+func TestNode_SetProp(t *testing.T) {
+	nd := NewNode("A", map[string]string{"color": "red"}).(PropNode)
+	props := nd.Props()
+	props["color"] = "blue"
+	if v, ok := nd.Prop("color"); !ok || v != "red" {
+		t.Fatalf("Expected Props to return a copy but %q", v)
+	}
+	if _, ok := nd.Prop("size"); ok {
+		t.Fatal("Expected no size")
+	}
+
+	// run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				nd.SetProp(fmt.Sprintf("k%d", i), fmt.Sprint(j))
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				nd.Prop("k0")
+				_ = len(nd.Props())
+			}
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 4; i++ {
+		if v, ok := nd.Prop(fmt.Sprintf("k%d", i)); !ok || v != "99" {
+			t.Fatalf("Expected k%d to be 99 but %q", i, v)
+		}
+	}
+}
+
+func TestGraph_TopChildren(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// D: A 20, B 30, E 2, F 11, T 16
+	edges, err := g.TopChildren(StringID("D"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := edgeStrings(edges); !reflect.DeepEqual(s, []string{"D -- 30.000 -→ B\n", "D -- 20.000 -→ A\n", "D -- 16.000 -→ T\n"}) {
+		t.Fatalf("Expected B, A, T but %v", s)
+	}
+
+	edges, err = g.TopChildren(StringID("D"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 5 {
+		t.Fatalf("Expected all 5 edges but %v", edgeStrings(edges))
+	}
+	if edges[4].Target().ID() != StringID("E") {
+		t.Fatalf("Expected the lightest edge to E last but %v", edgeStrings(edges))
+	}
+
+	if _, err := g.TopChildren(StringID("X"), 3); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+
+	// edges with the same weight are sorted by ascending target ID
+	g = NewGraph()
+	for _, id := range []string{"S", "A", "B", "C"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	g.AddEdge(StringID("S"), StringID("A"), 1)
+	g.AddEdge(StringID("S"), StringID("B"), 1)
+	g.AddEdge(StringID("S"), StringID("C"), 2)
+	edges, err = g.TopChildren(StringID("S"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := edgeStrings(edges); !reflect.DeepEqual(s, []string{"S -- 2.000 -→ C\n", "S -- 1.000 -→ A\n", "S -- 1.000 -→ B\n"}) {
+		t.Fatalf("Expected C, A, B but %v", s)
+	}
+}
+
+func TestIntID(t *testing.T) {
+	g := NewGraph()
+	for i := 1; i <= 10; i++ {
+		g.AddNode(NewNodeWithID(IntID(i), nil))
+	}
+	for i := 1; i < 10; i++ {
+		g.AddEdge(IntID(i), IntID(i+1), 1)
+	}
+	g.AddEdge(IntID(1), IntID(10), 20)
+	g.AddEdge(IntID(2), IntID(10), 5)
+
+	path, distance, err := Dijkstra(g, IntID(1), IntID(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(path, []ID{IntID(1), IntID(2), IntID(10)}) || distance[IntID(10)] != 6 {
+		t.Fatalf("Expected [1 2 10] with distance 6 but %v %f", path, distance[IntID(10)])
+	}
+	if g.HasNode(StringID("1")) {
+		t.Fatal("Expected StringID 1 not to match IntID 1")
+	}
+
+	// sorted by value, not by string
+	if rs := g.Sinks(); !reflect.DeepEqual(rs, []ID{IntID(10)}) {
+		t.Fatalf("Expected [10] but %v", rs)
+	}
+	ids := sortedIDs(g.Nodes())
+	if ids[1] != IntID(2) || ids[9] != IntID(10) {
+		t.Fatalf("Expected numeric order but %v", ids)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := ExportToJSON(g, buf); err != nil {
+		t.Fatal(err)
+	}
+	g2, err := NewGraphFromJSON(bytes.NewReader(buf.Bytes()), "", WithIntIDs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(g, g2) {
+		t.Fatalf("Expected equal graphs but\n%s\n%s", g, g2)
+	}
+
+	gbuf := new(bytes.Buffer)
+	if err := gob.NewEncoder(gbuf).Encode(g); err != nil {
+		t.Fatal(err)
+	}
+	g3 := NewGraph()
+	if err := gob.NewDecoder(gbuf).Decode(g3); err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(g, g3) {
+		t.Fatalf("Expected equal graphs but\n%s\n%s", g, g3)
+	}
+
+	if _, err := NewGraphFromJSON(strings.NewReader(`{"g": {"1": {"x": 1}}}`), "g", WithIntIDs()); err == nil {
+		t.Fatal("Expected error for a non-integer ID")
+	}
+	yg, err := NewGraphFromYAML(strings.NewReader("g:\n  1:\n    2: 3\n"), "g", WithIntIDs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := yg.EdgeWeight(IntID(1), IntID(2)); err != nil || v != 3 {
+		t.Fatalf("Expected weight 3 but %v %v", v, err)
+	}
+}
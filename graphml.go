@@ -0,0 +1,125 @@
+package goraph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// graphmlNamespace is the XML namespace of GraphML documents.
+const graphmlNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+// graphmlWeightKey is the key ID of the edge weight data.
+const graphmlWeightKey = "weight"
+
+type graphmlDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphmlKey `xml:"key"`
+	Graph   graphmlGraph `xml:"graph"`
+}
+
+type graphmlKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphmlGraph struct {
+	ID          string        `xml:"id,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+}
+
+type graphmlNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphmlData `xml:"data"`
+}
+
+type graphmlEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphmlData `xml:"data"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ExportToGraphML writes the graph to w as a directed GraphML document,
+// readable by tools such as Gephi and yEd. Node properties are written
+// as string data with one key per property name, and edge weights as
+// double data. Keys, nodes and edges are written in sorted order.
+func ExportToGraphML(g Graph, w io.Writer) error {
+	nodes := g.Nodes()
+	ids := sortedIDs(nodes)
+
+	// propKeys maps each property name to its key ID.
+	propKeys := make(map[string]string)
+	var names []string
+	for _, nd := range nodes {
+		for k := range nd.Props() {
+			if _, ok := propKeys[k]; !ok {
+				propKeys[k] = ""
+				names = append(names, k)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	doc := graphmlDocument{
+		Xmlns: graphmlNamespace,
+		Graph: graphmlGraph{
+			ID:          g.ID().String(),
+			EdgeDefault: "directed",
+		},
+	}
+	for i, k := range names {
+		propKeys[k] = fmt.Sprintf("d%d", i)
+		doc.Keys = append(doc.Keys, graphmlKey{ID: propKeys[k], For: "node", AttrName: k, AttrType: "string"})
+	}
+	doc.Keys = append(doc.Keys, graphmlKey{ID: graphmlWeightKey, For: "edge", AttrName: "weight", AttrType: "double"})
+
+	for _, id1 := range ids {
+		props := nodes[id1].Props()
+		gn := graphmlNode{ID: id1.String()}
+		for _, k := range names {
+			if v, ok := props[k]; ok {
+				gn.Data = append(gn.Data, graphmlData{Key: propKeys[k], Value: v})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gn)
+
+		cmap, err := g.ChildNodesOf(id1)
+		if err != nil {
+			return err
+		}
+		for _, id2 := range sortedIDs(cmap) {
+			weight, err := g.EdgeWeight(id1, id2)
+			if err != nil {
+				return err
+			}
+			doc.Graph.Edges = append(doc.Graph.Edges, graphmlEdge{
+				Source: id1.String(),
+				Target: id2.String(),
+				Data:   []graphmlData{{Key: graphmlWeightKey, Value: strconv.FormatFloat(weight, 'g', -1, 64)}},
+			})
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
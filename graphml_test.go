@@ -0,0 +1,55 @@
+package goraph
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+func TestExportToGraphML(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(NewNode("A&B", map[string]string{"label": `<"x">`}))
+	g.AddNode(NewNode("C", map[string]string{"color": "red", "label": "c"}))
+	g.AddNode(NewNode("D", nil))
+	g.AddEdge(StringID("A&B"), StringID("C"), 1.5)
+	g.AddEdge(StringID("C"), StringID("D"), 2)
+
+	var buf bytes.Buffer
+	if err := ExportToGraphML(g, &buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="color" attr.type="string"></key>
+  <key id="d1" for="node" attr.name="label" attr.type="string"></key>
+  <key id="weight" for="edge" attr.name="weight" attr.type="double"></key>
+  <graph edgedefault="directed">
+    <node id="A&amp;B">
+      <data key="d1">&lt;&#34;x&#34;&gt;</data>
+    </node>
+    <node id="C">
+      <data key="d0">red</data>
+      <data key="d1">c</data>
+    </node>
+    <node id="D"></node>
+    <edge source="A&amp;B" target="C">
+      <data key="weight">1.5</data>
+    </edge>
+    <edge source="C" target="D">
+      <data key="weight">2</data>
+    </edge>
+  </graph>
+</graphml>
+`
+	if buf.String() != expected {
+		t.Fatalf("Expected\n%s\nbut\n%s", expected, buf.String())
+	}
+
+	var doc graphmlDocument
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Graph.Nodes[0].ID != "A&B" || doc.Graph.Nodes[0].Data[0].Value != `<"x">` {
+		t.Fatalf("Expected unescaped ID and label but %+v", doc.Graph.Nodes[0])
+	}
+}
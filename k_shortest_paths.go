@@ -0,0 +1,181 @@
+package goraph
+
+import (
+	"container/heap"
+	"fmt"
+	"strings"
+)
+
+// KShortestPaths returns up to k loopless paths from source to target
+// in increasing order of total weight, with their total weights, using
+// Yen's algorithm with Dijkstra for the spur paths. It returns fewer
+// than k paths if there are not that many, and no paths if target
+// cannot be reached. Candidate paths of equal weight are taken in
+// order of their IDs.
+// This algorithm does not work with negative weight edges.
+// (https://en.wikipedia.org/wiki/Yen%27s_algorithm)
+//
+//	 0. KShortestPaths(G, source, target, K)
+//	 1.
+//	 2. 	A[0] = Dijkstra(G, source, target)
+//	 3. 	let B be a priority queue of candidate paths
+//	 4.
+//	 5. 	for k from 1 to K-1:
+//	 6. 		for i from 0 to len(A[k-1]) - 2:
+//	 7.
+//	 8. 			spurNode = A[k-1][i]
+//	 9. 			rootPath = A[k-1][0:i+1]
+//	10.
+//	11. 			for each path p in A:
+//	12. 				if rootPath == p[0:i+1]:
+//	13. 					remove edge (p[i], p[i+1]) from G
+//	14. 			for each node n in rootPath except spurNode:
+//	15. 				remove n from G
+//	16.
+//	17. 			spurPath = Dijkstra(G, spurNode, target)
+//	18. 			B.push(rootPath + spurPath) if not in B yet
+//	19. 			restore G
+//	20.
+//	21. 		if B is empty:
+//	22. 			break
+//	23. 		A[k] = B.extract_min()
+//
+func KShortestPaths(g Graph, source, target ID, k int) ([][]ID, []float64, error) {
+	if k < 1 {
+		return nil, nil, fmt.Errorf("k must be positive but %d", k)
+	}
+	if _, err := g.Node(source); err != nil {
+		return nil, nil, err
+	}
+	if _, err := g.Node(target); err != nil {
+		return nil, nil, err
+	}
+	if err := checkNonNegative(g); err != nil {
+		return nil, nil, err
+	}
+
+	// A[0] = Dijkstra(G, source, target)
+	path, distance, _, err := aStar(g, source, target, nil, nil, nil)
+	if err == ErrNoPath {
+		return [][]ID{}, []float64{}, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	paths := [][]ID{path}
+	distances := []float64{distance}
+
+	// let B be a priority queue of candidate paths
+	candidates := &pathHeap{}
+	seen := map[string]bool{pathKey(path): true}
+
+	// for k from 1 to K-1:
+	for len(paths) < k {
+		last := paths[len(paths)-1]
+
+		// for i from 0 to len(A[k-1]) - 2:
+		rootDistance := 0.0
+		for i := 0; i < len(last)-1; i++ {
+			spurNode := last[i]
+			rootPath := last[:i+1]
+
+			// remove the edges and nodes from G
+			// by skipping them in Dijkstra
+			removedEdges := make(map[[2]ID]bool)
+			for _, p := range paths {
+				if len(p) > i+1 && equalPath(p[:i+1], rootPath) {
+					removedEdges[[2]ID{p[i], p[i+1]}] = true
+				}
+			}
+			removedNodes := make(map[ID]bool)
+			for _, n := range rootPath[:i] {
+				removedNodes[n] = true
+			}
+			skip := func(u, v ID) bool {
+				return removedNodes[v] || removedEdges[[2]ID{u, v}]
+			}
+
+			// spurPath = Dijkstra(G, spurNode, target)
+			spurPath, spurDistance, _, err := aStar(g, spurNode, target, nil, nil, skip)
+			if err == nil {
+				// B.push(rootPath + spurPath) if not in B yet
+				total := append(append([]ID{}, rootPath[:i]...), spurPath...)
+				if key := pathKey(total); !seen[key] {
+					seen[key] = true
+					heap.Push(candidates, weightedPath{path: total, distance: rootDistance + spurDistance, key: key})
+				}
+			} else if err != ErrNoPath {
+				return nil, nil, err
+			}
+
+			weight, err := g.EdgeWeight(last[i], last[i+1])
+			if err != nil {
+				return nil, nil, err
+			}
+			rootDistance += weight
+		}
+
+		// if B is empty:
+		if candidates.Len() == 0 {
+			break
+		}
+
+		// A[k] = B.extract_min()
+		wp := heap.Pop(candidates).(weightedPath)
+		paths = append(paths, wp.path)
+		distances = append(distances, wp.distance)
+	}
+
+	return paths, distances, nil
+}
+
+func equalPath(a, b []ID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// pathKey returns a string that identifies the path.
+func pathKey(path []ID) string {
+	ss := make([]string, len(path))
+	for i, id := range path {
+		ss[i] = id.String()
+	}
+	return strings.Join(ss, "\x00")
+}
+
+// weightedPath is a candidate path in KShortestPaths.
+type weightedPath struct {
+	path     []ID
+	distance float64
+	key      string
+}
+
+// pathHeap is a min-heap of weightedPaths, ordered
+// by distance and then by key.
+type pathHeap []weightedPath
+
+func (h pathHeap) Len() int      { return len(h) }
+func (h pathHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h pathHeap) Less(i, j int) bool {
+	if h[i].distance != h[j].distance {
+		return h[i].distance < h[j].distance
+	}
+	return h[i].key < h[j].key
+}
+
+func (h *pathHeap) Push(x interface{}) {
+	*h = append(*h, x.(weightedPath))
+}
+
+func (h *pathHeap) Pop() interface{} {
+	heapSize := len(*h)
+	lastPath := (*h)[heapSize-1]
+	*h = (*h)[0 : heapSize-1]
+	return lastPath
+}
@@ -0,0 +1,73 @@
+package goraph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestKShortestPaths(t *testing.T) {
+	// the graph from the Wikipedia article on Yen's algorithm
+	g := NewGraph()
+	for _, id := range []string{"C", "D", "E", "F", "G", "H"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	for _, elem := range []struct {
+		id1, id2 string
+		weight   float64
+	}{
+		{"C", "D", 3}, {"C", "E", 2}, {"D", "F", 4}, {"E", "D", 1},
+		{"E", "F", 2}, {"E", "G", 3}, {"F", "G", 2}, {"F", "H", 1},
+		{"G", "H", 2},
+	} {
+		g.AddEdge(StringID(elem.id1), StringID(elem.id2), elem.weight)
+	}
+
+	paths, distances, err := KShortestPaths(g, StringID("C"), StringID("H"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]ID{
+		{StringID("C"), StringID("E"), StringID("F"), StringID("H")},
+		{StringID("C"), StringID("E"), StringID("G"), StringID("H")},
+		{StringID("C"), StringID("D"), StringID("F"), StringID("H")},
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("Expected %v but %v", expected, paths)
+	}
+	if !reflect.DeepEqual(distances, []float64{5, 7, 8}) {
+		t.Fatalf("Expected [5 7 8] but %v", distances)
+	}
+
+	// there are only 7 loopless paths
+	paths, distances, err = KShortestPaths(g, StringID("C"), StringID("H"), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 7 || len(distances) != 7 {
+		t.Fatalf("Expected 7 paths but %v", paths)
+	}
+	seen := make(map[string]bool)
+	for i, path := range paths {
+		if key := pathKey(path); seen[key] {
+			t.Fatalf("Expected distinct paths but %v twice", path)
+		} else {
+			seen[key] = true
+		}
+		if i > 0 && distances[i-1] > distances[i] {
+			t.Fatalf("Expected increasing distances but %v", distances)
+		}
+	}
+
+	if _, _, err := KShortestPaths(g, StringID("C"), StringID("X"), 3); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+	paths, _, err = KShortestPaths(g, StringID("H"), StringID("C"), 3)
+	if err != nil || len(paths) != 0 {
+		t.Fatalf("Expected no paths but %v %v", paths, err)
+	}
+	g.AddEdge(StringID("H"), StringID("C"), -1)
+	if _, _, err := KShortestPaths(g, StringID("C"), StringID("H"), 3); err == nil {
+		t.Fatal("Expected error for a negative weight")
+	}
+}
@@ -0,0 +1,68 @@
+package goraph
+
+import (
+	"fmt"
+	"math"
+)
+
+// LaplacianMatrix returns the Laplacian matrix L = D - A of the graph
+// treated as undirected, where A_ij is the sum of the weights of the
+// edges between i and j in both directions and D is the diagonal matrix
+// of the weighted degrees. Self-loops are ignored. If normalized is
+// true, it returns the symmetric normalized Laplacian
+// I - D^-1/2 A D^-1/2 instead, where the row and column of an isolated
+// node are all 0. The rows and columns follow the order of ids, which
+// is sorted. It returns error if an edge has a negative weight.
+// (https://en.wikipedia.org/wiki/Laplacian_matrix)
+func (g *graph) LaplacianMatrix(normalized bool) ([][]float64, []ID, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ids := sortedIDs(g.nodes)
+	index := make(map[ID]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	n := len(ids)
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+	}
+	degree := make([]float64, n)
+	for id1, tmap := range g.nodeChildren {
+		for id2, weight := range tmap {
+			if weight < 0 {
+				return nil, nil, fmt.Errorf("weight from %s to %s must not be negative but %f", id1, id2, weight)
+			}
+			i, j := index[id1], index[id2]
+			if i == j {
+				continue
+			}
+			m[i][j] -= weight
+			m[j][i] -= weight
+			degree[i] += weight
+			degree[j] += weight
+		}
+	}
+	for i := range m {
+		m[i][i] = degree[i]
+	}
+	if !normalized {
+		return m, ids, nil
+	}
+
+	for i := range m {
+		if degree[i] == 0 {
+			// an isolated node has no off-diagonal entries
+			m[i][i] = 0
+			continue
+		}
+		for j := range m[i] {
+			if degree[j] != 0 {
+				m[i][j] /= math.Sqrt(degree[i] * degree[j])
+			}
+		}
+	}
+	return m, ids, nil
+}
@@ -0,0 +1,59 @@
+package goraph
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGraph_LaplacianMatrix(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"D", "C", "B", "A"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("B"), StringID("A"), 1)
+	g.AddEdge(StringID("B"), StringID("C"), 2)
+	g.AddEdge(StringID("C"), StringID("C"), 5)
+
+	m, ids, err := g.LaplacianMatrix(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []ID{StringID("A"), StringID("B"), StringID("C"), StringID("D")}) {
+		t.Fatalf("Expected [A B C D] but %v", ids)
+	}
+	expected := [][]float64{
+		{2, -2, 0, 0},
+		{-2, 4, -2, 0},
+		{0, -2, 2, 0},
+		{0, 0, 0, 0},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Fatalf("Expected %v but %v", expected, m)
+	}
+
+	m, _, err = g.LaplacianMatrix(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := -1 / math.Sqrt(2)
+	expected = [][]float64{
+		{1, r, 0, 0},
+		{r, 1, r, 0},
+		{0, r, 1, 0},
+		{0, 0, 0, 0},
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if math.IsNaN(m[i][j]) || math.Abs(m[i][j]-expected[i][j]) > EqualEpsilon {
+				t.Fatalf("Expected %v but %v", expected, m)
+			}
+		}
+	}
+
+	g.AddEdge(StringID("A"), StringID("D"), -1)
+	if _, _, err := g.LaplacianMatrix(false); err == nil {
+		t.Fatal("Expected error for a negative weight")
+	}
+}
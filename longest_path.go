@@ -0,0 +1,85 @@
+package goraph
+
+// LongestPath returns the path with the largest total weight from
+// source to target and its weight, using dynamic programming over a
+// topological order. Negative weights are allowed. It returns ErrCyclic
+// if the graph has a cycle, since the longest path is then unbounded
+// or NP-hard to find, and ErrNoPath if target cannot be reached.
+// Among paths with the same weight, the one through the smaller
+// predecessor IDs is returned.
+// (https://en.wikipedia.org/wiki/Longest_path_problem#Acyclic_graphs)
+//
+//	 0. LongestPath(G, source, target)
+//	 1.
+//	 2. 	L = TopologicalSort(G)
+//	 3. 	distance[source] = 0
+//	 4.
+//	 5. 	for each vertex u in L:
+//	 6. 		if distance[u] is undefined:
+//	 7. 			continue
+//	 8.
+//	 9. 		for each child vertex v of u:
+//	10. 			if distance[v] is undefined or
+//	11. 			   distance[v] < distance[u] + weight(u, v):
+//	12. 				distance[v] = distance[u] + weight(u, v)
+//	13. 				prev[v] = u
+//	14.
+//	15. 	return path to target
+//
+func LongestPath(g Graph, source, target ID) ([]ID, float64, error) {
+	if _, err := g.Node(source); err != nil {
+		return nil, 0, err
+	}
+	if _, err := g.Node(target); err != nil {
+		return nil, 0, err
+	}
+
+	// L = TopologicalSort(G)
+	order, isDAG := TopologicalSort(g)
+	if !isDAG {
+		return nil, 0, ErrCyclic
+	}
+
+	// distance[source] = 0
+	distance := map[ID]float64{source: 0}
+	prev := make(map[ID]ID)
+
+	// for each vertex u in L:
+	for _, u := range order {
+		// if distance[u] is undefined:
+		if _, ok := distance[u]; !ok {
+			continue
+		}
+
+		// for each child vertex v of u:
+		cmap, err := g.ChildNodesOf(u)
+		if err != nil {
+			return nil, 0, err
+		}
+		for v := range cmap {
+			weight, err := g.EdgeWeight(u, v)
+			if err != nil {
+				return nil, 0, err
+			}
+			alt := distance[u] + weight
+
+			d, ok := distance[v]
+			if !ok || d < alt || (d == alt && lessID(u, prev[v])) {
+				distance[v] = alt
+				prev[v] = u
+			}
+		}
+	}
+
+	if _, ok := distance[target]; !ok {
+		return nil, 0, ErrNoPath
+	}
+
+	// return path to target
+	path := []ID{target}
+	for v := target; v != source; {
+		v = prev[v]
+		path = append([]ID{v}, path...)
+	}
+	return path, distance[target], nil
+}
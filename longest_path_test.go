@@ -0,0 +1,67 @@
+package goraph
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLongestPath(t *testing.T) {
+	// a task schedule, where each edge weight is the
+	// duration of the task at its source
+	data := `schedule:
+  start:
+    design: 0
+    order: 0
+  design:
+    build: 5
+    test: 5
+  order:
+    build: 2
+  build:
+    test: 10
+  test:
+    ship: 3
+  ship:
+    end: 1
+  spare: {}
+`
+	g, err := NewGraphFromYAML(strings.NewReader(data), "schedule")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, weight, err := LongestPath(g, StringID("start"), StringID("end"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ID{StringID("start"), StringID("design"), StringID("build"), StringID("test"), StringID("ship"), StringID("end")}
+	if !reflect.DeepEqual(path, expected) || weight != 19 {
+		t.Fatalf("Expected %v with weight 19 but %v %f", expected, path, weight)
+	}
+
+	// the shortest path skips build
+	spath, distance, err := Dijkstra(g, StringID("start"), StringID("end"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(spath, path) || distance[StringID("end")] != 9 {
+		t.Fatalf("Expected a shorter path of 9 but %v %f", spath, distance[StringID("end")])
+	}
+
+	if path, weight, err := LongestPath(g, StringID("build"), StringID("build")); err != nil || len(path) != 1 || weight != 0 {
+		t.Fatalf("Expected [build] but %v %f %v", path, weight, err)
+	}
+	if _, _, err := LongestPath(g, StringID("start"), StringID("spare")); !errors.Is(err, ErrNoPath) {
+		t.Fatalf("Expected ErrNoPath but %v", err)
+	}
+	if _, _, err := LongestPath(g, StringID("start"), StringID("X")); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+
+	g.AddEdge(StringID("ship"), StringID("design"), 1)
+	if _, _, err := LongestPath(g, StringID("start"), StringID("end")); !errors.Is(err, ErrCyclic) {
+		t.Fatalf("Expected ErrCyclic but %v", err)
+	}
+}
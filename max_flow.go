@@ -0,0 +1,153 @@
+package goraph
+
+import "fmt"
+
+// MaxFlow returns the maximum flow from source to sink using
+// Edmonds-Karp algorithm, with edge weights as capacities. It also
+// returns the flow on each edge, mapped from source to target node ID.
+// It returns 0 if sink cannot be reached from source.
+// Time complexity is O(|V||E|^2).
+// (https://en.wikipedia.org/wiki/Edmonds%E2%80%93Karp_algorithm)
+//
+//	 0. EdmondsKarp(G, s, t)
+//	 1.
+//	 2. 	for each edge (u, v):
+//	 3. 		flow(u, v) = 0
+//	 4.
+//	 5. 	while there is a path p from s to t in the residual
+//	 6. 	graph, found by breadth-first search:
+//	 7.
+//	 8. 		b = min(capacity(u, v) - flow(u, v) for (u, v) in p)
+//	 9.
+//	10. 		for each edge (u, v) in p:
+//	11. 			flow(u, v) = flow(u, v) + b
+//	12. 			flow(v, u) = flow(v, u) - b
+//	13.
+//	14. 		maxFlow = maxFlow + b
+//
+func MaxFlow(g Graph, source, sink ID) (float64, map[string]map[string]float64, error) {
+	if _, err := g.Node(source); err != nil {
+		return 0, nil, err
+	}
+	if _, err := g.Node(sink); err != nil {
+		return 0, nil, err
+	}
+	if source == sink {
+		return 0, nil, fmt.Errorf("source and sink must be different but both are %s", source)
+	}
+
+	// capacity of each edge, and neighbors in the residual
+	// graph in both directions sorted to be deterministic.
+	capacity := make(map[ID]map[ID]float64)
+	neighbors := make(map[ID][]ID)
+	nodes := g.Nodes()
+	for id := range nodes {
+		capacity[id] = make(map[ID]float64)
+	}
+	for u := range nodes {
+		cmap, err := g.ChildNodesOf(u)
+		if err != nil {
+			return 0, nil, err
+		}
+		for v := range cmap {
+			weight, err := g.EdgeWeight(u, v)
+			if err != nil {
+				return 0, nil, err
+			}
+			if weight < 0 {
+				return 0, nil, fmt.Errorf("capacity from %s to %s must not be negative but %f", u, v, weight)
+			}
+			capacity[u][v] = weight
+		}
+	}
+	for u := range nodes {
+		nmap := make(map[ID]Node)
+		for v := range capacity[u] {
+			nmap[v] = nodes[v]
+		}
+		pmap, err := g.ParentNodesOf(u)
+		if err != nil {
+			return 0, nil, err
+		}
+		for v, nd := range pmap {
+			nmap[v] = nd
+		}
+		neighbors[u] = sortedIDs(nmap)
+	}
+
+	maxFlow, flow := edmondsKarp(capacity, neighbors, source, sink)
+
+	rs := make(map[string]map[string]float64)
+	for u, cmap := range capacity {
+		if len(cmap) == 0 {
+			continue
+		}
+		rs[u.String()] = make(map[string]float64)
+		for v := range cmap {
+			// only the positive part of the net flow runs along u → v
+			rs[u.String()][v.String()] = 0
+			if flow[u][v] > 0 {
+				rs[u.String()][v.String()] = flow[u][v]
+			}
+		}
+	}
+	return maxFlow, rs, nil
+}
+
+// edmondsKarp runs the loop of MaxFlow over the capacity of each edge
+// and the residual neighbors of each node, and returns the maximum flow
+// and the flow on each edge, which is negative in the reverse direction.
+// W must be signed for the reverse flow.
+func edmondsKarp[W Number](capacity map[ID]map[ID]W, neighbors map[ID][]ID, source, sink ID) (W, map[ID]map[ID]W) {
+	// flow(u, v) = 0
+	flow := make(map[ID]map[ID]W)
+	for id := range capacity {
+		flow[id] = make(map[ID]W)
+	}
+
+	var maxFlow W
+	for {
+		// breadth-first search in the residual graph
+		prev := map[ID]ID{source: source}
+		q := []ID{source}
+		for len(q) != 0 {
+			u := q[0]
+			q = q[1:]
+			if u == sink {
+				break
+			}
+			for _, v := range neighbors[u] {
+				if _, ok := prev[v]; ok {
+					continue
+				}
+				if capacity[u][v]-flow[u][v] > 0 {
+					prev[v] = u
+					q = append(q, v)
+				}
+			}
+		}
+		if _, ok := prev[sink]; !ok {
+			break
+		}
+
+		// b = min(capacity(u, v) - flow(u, v) for (u, v) in p)
+		b := capacity[prev[sink]][sink] - flow[prev[sink]][sink]
+		for v := sink; v != source; v = prev[v] {
+			u := prev[v]
+			if r := capacity[u][v] - flow[u][v]; r < b {
+				b = r
+			}
+		}
+
+		// for each edge (u, v) in p:
+		for v := sink; v != source; v = prev[v] {
+			u := prev[v]
+			flow[u][v] += b
+			flow[v][u] -= b
+		}
+
+		maxFlow += b
+	}
+
+	return maxFlow, flow
+}
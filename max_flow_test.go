@@ -0,0 +1,86 @@
+package goraph
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMaxFlow(t *testing.T) {
+	for name, expected := range map[string]float64{
+		"graph_00": 85.0,
+		"graph_10": 28.0,
+		"graph_16": 28.0,
+	} {
+		f, err := os.Open("testdata/graph.json")
+		if err != nil {
+			t.Error(err)
+		}
+		defer f.Close()
+		g, err := NewGraphFromJSON(f, name)
+		if err != nil {
+			t.Error(err)
+		}
+		maxFlow, flow, err := MaxFlow(g, StringID("S"), StringID("T"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if maxFlow != expected {
+			t.Errorf("%s | Expected %.2f but %.2f", name, expected, maxFlow)
+		}
+
+		// capacity and conservation constraints
+		balance := make(map[string]float64)
+		for u, tmap := range flow {
+			for v, f := range tmap {
+				weight, err := g.EdgeWeight(StringID(u), StringID(v))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if f < 0 || f > weight {
+					t.Errorf("%s | flow from %s to %s must be within [0, %.2f] but %.2f", name, u, v, weight, f)
+				}
+				balance[u] -= f
+				balance[v] += f
+			}
+		}
+		for id, b := range balance {
+			switch id {
+			case "S":
+				if b != -expected {
+					t.Errorf("%s | Expected %.2f out of S but %.2f", name, expected, -b)
+				}
+			case "T":
+				if b != expected {
+					t.Errorf("%s | Expected %.2f into T but %.2f", name, expected, b)
+				}
+			default:
+				if b != 0 {
+					t.Errorf("%s | Expected flow conservation at %s but %.2f", name, id, b)
+				}
+			}
+		}
+	}
+}
+
+func TestMaxFlow_errors(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_05")
+	if err != nil {
+		t.Error(err)
+	}
+	if _, _, err := MaxFlow(g, StringID("A"), StringID("A")); err == nil {
+		t.Error("Expected an error when source is sink")
+	}
+	if _, _, err := MaxFlow(g, StringID("X"), StringID("A")); err == nil {
+		t.Error("Expected an error for unknown source")
+	}
+	// there is no path from A to D
+	maxFlow, _, err := MaxFlow(g, StringID("A"), StringID("D"))
+	if err != nil || maxFlow != 0 {
+		t.Errorf("Expected 0 but %.2f %v", maxFlow, err)
+	}
+}
@@ -0,0 +1,59 @@
+package goraph
+
+// The sizes below are rough per-item costs on a 64-bit platform, used
+// by ApproxMemoryBytes. Map entries include a share of the bucket
+// overhead, assuming maps are about half full.
+const (
+	// graphBytes is the graph struct with its mutexes and map headers.
+	graphBytes = 160
+
+	// nodeEntryBytes is an entry of the nodes map,
+	// with an ID and a Node interface.
+	nodeEntryBytes = 48
+
+	// nodeBytes is a node struct with its mutex and map headers,
+	// and a StringID of a short string.
+	nodeBytes = 80
+
+	// adjacencyMapBytes is an inner map of nodeChildren or
+	// nodeParents, including its entry in the outer map.
+	adjacencyMapBytes = 96
+
+	// edgeEntryBytes is an entry of an inner adjacency map, with
+	// an ID and a float64. Each edge is stored twice.
+	edgeEntryBytes = 40
+
+	// propEntryBytes is an entry of a property map with two string
+	// headers, not counting the bytes of the key and the value.
+	propEntryBytes = 48
+)
+
+// ApproxMemoryBytes estimates the heap usage of the graph as
+//
+//	graphBytes
+//	+ |V| * (nodeEntryBytes + nodeBytes)
+//	+ (number of inner adjacency maps) * adjacencyMapBytes
+//	+ |E| * 2 * edgeEntryBytes
+//	+ (number of properties) * propEntryBytes
+//	+ (total length of property keys and values)
+//
+// The estimate ignores memory shared with other graphs, such as nodes
+// added to more than one graph, and the long tail of map growth, so
+// it can be off by a constant factor but scales with the graph.
+func (g *graph) ApproxMemoryBytes() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	n := graphBytes
+	n += len(g.nodes) * (nodeEntryBytes + nodeBytes)
+	n += (len(g.nodeChildren) + len(g.nodeParents)) * adjacencyMapBytes
+	for _, cmap := range g.nodeChildren {
+		n += len(cmap) * 2 * edgeEntryBytes
+	}
+	for _, nd := range g.nodes {
+		for k, v := range nd.Props() {
+			n += propEntryBytes + len(k) + len(v)
+		}
+	}
+	return n
+}
@@ -0,0 +1,53 @@
+package goraph
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGraph_ApproxMemoryBytes(t *testing.T) {
+	g := NewGraph()
+	last := g.ApproxMemoryBytes()
+	if last <= 0 {
+		t.Fatalf("Expected a positive estimate but %d", last)
+	}
+	grows := func(what string) {
+		n := g.ApproxMemoryBytes()
+		if n <= last {
+			t.Fatalf("Expected the estimate to grow after %s but %d <= %d", what, n, last)
+		}
+		last = n
+	}
+
+	for i := 0; i < 100; i++ {
+		g.AddNode(NewNode(fmt.Sprintf("n%d", i), nil))
+		grows("adding a node")
+	}
+	for i := 1; i < 100; i++ {
+		g.AddEdge(StringID("n0"), StringID(fmt.Sprintf("n%d", i)), 1)
+		grows("adding an edge")
+	}
+	nd, _ := g.Node(StringID("n0"))
+	nd.(PropNode).SetProp("label", "root")
+	grows("setting a property")
+	nd.(PropNode).SetProp("label", "the root node")
+	grows("setting a longer property")
+
+	// updating a weight does not change the size
+	g.AddEdge(StringID("n0"), StringID("n1"), 1)
+	if n := g.ApproxMemoryBytes(); n != last {
+		t.Fatalf("Expected %d but %d", last, n)
+	}
+
+	// roughly linear in the number of edges
+	h := NewGraph()
+	for i := 0; i < 100; i++ {
+		h.AddNode(NewNode(fmt.Sprintf("n%d", i), nil))
+	}
+	for i := 0; i < 200; i++ {
+		h.AddEdge(StringID(fmt.Sprintf("n%d", i%100)), StringID(fmt.Sprintf("n%d", (i*7+1)%100)), 1)
+	}
+	if small, large := g.ApproxMemoryBytes(), h.ApproxMemoryBytes(); large <= small {
+		t.Fatalf("Expected more memory for more edges but %d <= %d", large, small)
+	}
+}
@@ -0,0 +1,141 @@
+package goraph
+
+import (
+	"fmt"
+	"math"
+)
+
+// MinCut returns the weight of the global minimum cut and the two
+// node partitions it separates, using Stoer-Wagner algorithm. The
+// graph is treated as undirected, where the weight between two nodes
+// is the sum of the weights of the edges in both directions, and
+// self-loops are ignored. Each partition is sorted, and the partition
+// with the smallest ID comes first. It returns ErrDisconnected with a
+// cut weight of 0 if the graph is not connected, and error if the graph
+// has fewer than 2 nodes or an edge has a negative weight.
+// Time complexity is O(|V|^3).
+// (https://en.wikipedia.org/wiki/Stoer%E2%80%93Wagner_algorithm)
+//
+//	 0. MinCut(G)
+//	 1.
+//	 2. 	best = ∞
+//	 3. 	while G has more than one vertex:
+//	 4.
+//	 5. 		A = {any vertex}
+//	 6. 		while A ≠ V:
+//	 7. 			add to A the vertex most tightly
+//	 8. 			connected to A
+//	 9.
+//	10. 		s, t = the last two vertices added
+//	11. 		if weight(t, A - t) < best:
+//	12. 			best = weight(t, A - t)
+//	13. 			bestPartition = vertices merged into t
+//	14.
+//	15. 		merge t into s
+//
+func MinCut(g Graph) (float64, [][]ID, error) {
+	ids := sortedIDs(g.Nodes())
+	if len(ids) < 2 {
+		return 0, nil, fmt.Errorf("graph must have at least 2 nodes but %d", len(ids))
+	}
+	if err := checkNonNegative(g); err != nil {
+		return 0, nil, err
+	}
+	if !g.IsConnected() {
+		return 0, minCutPartitions(ids, BFS(g, ids[0])), ErrDisconnected
+	}
+
+	n := len(ids)
+	index := make(map[ID]int, n)
+	for i, id := range ids {
+		index[id] = i
+	}
+	w := make([][]float64, n)
+	for i := range w {
+		w[i] = make([]float64, n)
+	}
+	for _, edge := range g.Edges() {
+		i, j := index[edge.Source().ID()], index[edge.Target().ID()]
+		if i != j {
+			w[i][j] += edge.Weight()
+			w[j][i] += edge.Weight()
+		}
+	}
+
+	// merged[i] holds the vertices that have been merged into i
+	merged := make([][]ID, n)
+	active := make([]int, n)
+	for i := range ids {
+		merged[i] = []ID{ids[i]}
+		active[i] = i
+	}
+
+	// best = ∞
+	best := math.Inf(1)
+	var bestPartition []ID
+
+	// while G has more than one vertex:
+	for len(active) > 1 {
+		// A = {any vertex}
+		// while A ≠ V:
+		//   add to A the vertex most tightly connected to A
+		added := make([]bool, n)
+		tightness := make([]float64, n)
+		s, t := -1, -1
+		for range active {
+			next := -1
+			for _, v := range active {
+				if !added[v] && (next == -1 || tightness[v] > tightness[next]) {
+					next = v
+				}
+			}
+			added[next] = true
+			s, t = t, next
+			for _, v := range active {
+				if !added[v] {
+					tightness[v] += w[next][v]
+				}
+			}
+		}
+
+		// if weight(t, A - t) < best:
+		if tightness[t] < best {
+			best = tightness[t]
+			bestPartition = append([]ID{}, merged[t]...)
+		}
+
+		// merge t into s
+		merged[s] = append(merged[s], merged[t]...)
+		for _, v := range active {
+			w[s][v] += w[t][v]
+			w[v][s] = w[s][v]
+		}
+		w[s][s] = 0
+		for i, v := range active {
+			if v == t {
+				active = append(active[:i], active[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return best, minCutPartitions(ids, bestPartition), nil
+}
+
+// minCutPartitions splits the sorted ids into the part and the rest,
+// each sorted, with the partition holding ids[0] first.
+func minCutPartitions(ids []ID, part []ID) [][]ID {
+	in := make(map[ID]bool, len(part))
+	for _, id := range part {
+		in[id] = true
+	}
+	first, second := []ID{}, []ID{}
+	for _, id := range ids {
+		if in[id] == in[ids[0]] {
+			first = append(first, id)
+		} else {
+			second = append(second, id)
+		}
+	}
+	return [][]ID{first, second}
+}
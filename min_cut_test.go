@@ -0,0 +1,54 @@
+package goraph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMinCut(t *testing.T) {
+	// the graph from the paper by Stoer and Wagner,
+	// whose minimum cut of weight 4 is {3, 4, 7, 8}
+	g := NewGraph()
+	for _, id := range []string{"1", "2", "3", "4", "5", "6", "7", "8"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	for _, elem := range []struct {
+		id1, id2 string
+		weight   float64
+	}{
+		{"1", "2", 2}, {"1", "5", 3}, {"2", "3", 3}, {"2", "5", 2},
+		{"2", "6", 2}, {"3", "4", 4}, {"3", "7", 2}, {"4", "7", 2},
+		{"4", "8", 2}, {"5", "6", 3}, {"6", "7", 1}, {"7", "8", 3},
+	} {
+		g.AddEdge(StringID(elem.id1), StringID(elem.id2), elem.weight)
+	}
+
+	weight, partitions, err := MinCut(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weight != 4 {
+		t.Fatalf("Expected minimum cut 4 but %f", weight)
+	}
+	expected := [][]ID{
+		{StringID("1"), StringID("2"), StringID("5"), StringID("6")},
+		{StringID("3"), StringID("4"), StringID("7"), StringID("8")},
+	}
+	if !reflect.DeepEqual(partitions, expected) {
+		t.Fatalf("Expected %v but %v", expected, partitions)
+	}
+
+	g.AddNode(NewNode("9", nil))
+	weight, partitions, err = MinCut(g)
+	if !errors.Is(err, ErrDisconnected) || weight != 0 {
+		t.Fatalf("Expected ErrDisconnected but %f %v", weight, err)
+	}
+	if len(partitions) != 2 || !reflect.DeepEqual(partitions[1], []ID{StringID("9")}) {
+		t.Fatalf("Expected 9 alone but %v", partitions)
+	}
+
+	if _, _, err := MinCut(NewGraph()); err == nil {
+		t.Fatal("Expected error for an empty graph")
+	}
+}
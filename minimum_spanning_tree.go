@@ -98,6 +98,101 @@ func Kruskal(g Graph) (map[Edge]struct{}, error) {
 	return A, nil
 }
 
+// MinimumSpanningTree returns the edges of the minimum spanning tree
+// and their total weight, using Kruskal's algorithm with disjoint sets.
+// The graph is interpreted as undirected: an edge in either direction
+// connects the two nodes, and when both directions exist the lighter
+// one is considered first. Edges with the same weight are considered
+// in the order of source and target IDs, so the result is deterministic.
+// It returns ErrDisconnected if the graph is not connected.
+func MinimumSpanningTree(g Graph) ([]Edge, float64, error) {
+	tree, total, _, err := MinimumSpanningTreeUnique(g)
+	return tree, total, err
+}
+
+// MinimumSpanningTreeUnique returns the same tree as MinimumSpanningTree,
+// and true if it is the only minimum spanning tree of the graph. Edges
+// in both directions between two nodes count as one undirected edge.
+// The tree is not unique when some edges of the same weight could
+// replace each other, which Kruskal's algorithm detects by counting,
+// for each group of edges with the same weight, the edges that join
+// two trees before and after adding the group.
+func MinimumSpanningTreeUnique(g Graph) ([]Edge, float64, bool, error) {
+	forests := NewForests()
+	nodes := g.Nodes()
+	for _, nd := range nodes {
+		MakeDisjointSet(forests, nd.String())
+	}
+
+	// lightest keeps the lighter direction between two nodes
+	lightest := make(map[[2]ID]Edge)
+	for id1, nd1 := range nodes {
+		cmap, err := g.ChildNodesOf(id1)
+		if err != nil {
+			return nil, 0, false, err
+		}
+		for id2, nd2 := range cmap {
+			weight, err := g.EdgeWeight(id1, id2)
+			if err != nil {
+				return nil, 0, false, err
+			}
+			edge := NewEdge(nd1, nd2, weight, make(map[string]string))
+			key := [2]ID{id1, id2}
+			if lessID(id2, id1) {
+				key = [2]ID{id2, id1}
+			}
+			if prev, ok := lightest[key]; !ok || EdgeSlice([]Edge{edge, prev}).Less(0, 1) {
+				lightest[key] = edge
+			}
+		}
+	}
+	edges := make([]Edge, 0, len(lightest))
+	for _, edge := range lightest {
+		edges = append(edges, edge)
+	}
+	sort.Sort(EdgeSlice(edges))
+
+	tree := []Edge{}
+	total := 0.0
+	unique := true
+	for i := 0; i < len(edges); {
+		j := i
+		for j < len(edges) && edges[j].Weight() == edges[i].Weight() {
+			j++
+		}
+
+		usable := 0
+		for _, edge := range edges[i:j] {
+			ds1 := FindSet(forests, edge.Source().String())
+			ds2 := FindSet(forests, edge.Target().String())
+			if ds1.represent != ds2.represent {
+				usable++
+			}
+		}
+		added := 0
+		for _, edge := range edges[i:j] {
+			ds1 := FindSet(forests, edge.Source().String())
+			ds2 := FindSet(forests, edge.Target().String())
+			if ds1.represent == ds2.represent {
+				continue
+			}
+			tree = append(tree, edge)
+			total += edge.Weight()
+			Union(forests, ds1, ds2)
+			added++
+		}
+		if usable > added {
+			unique = false
+		}
+		i = j
+	}
+
+	if len(nodes) > 0 && len(tree) != len(nodes)-1 {
+		return nil, 0, false, ErrDisconnected
+	}
+	return tree, total, unique, nil
+}
+
 // Prim finds the minimum spanning tree with min-heap (priority queue).
 // (http://en.wikipedia.org/wiki/Prim%27s_algorithm)
 //
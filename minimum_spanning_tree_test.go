@@ -3,6 +3,7 @@ package goraph
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -55,3 +56,89 @@ func TestGraph_Prim_13(t *testing.T) {
 		fmt.Println("Prim from graph_13:", A, "with", v)
 	}
 }
+
+func TestMinimumSpanningTree_13(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_13")
+	if err != nil {
+		t.Error(err)
+	}
+	tree, total, err := MinimumSpanningTree(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 37.0 {
+		t.Errorf("Expected total 37.0 but %.2f", total)
+	}
+	if len(tree) != g.NodeCount()-1 {
+		t.Errorf("Expected %d edges but %v", g.NodeCount()-1, tree)
+	}
+}
+
+func TestMinimumSpanningTree_disconnected(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D"} {
+		g.AddNode(NewNode(id, make(map[string]string)))
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("D"), StringID("C"), 2)
+	if _, _, err := MinimumSpanningTree(g); err != ErrDisconnected {
+		t.Fatalf("Expected ErrDisconnected but %v", err)
+	}
+}
+
+func TestMinimumSpanningTreeUnique(t *testing.T) {
+	build := func(weightDA float64) Graph {
+		g := NewGraph()
+		for _, id := range []string{"D", "C", "B", "A"} {
+			g.AddNode(NewNode(id, nil))
+		}
+		g.AddEdge(StringID("C"), StringID("D"), 1)
+		g.AddEdge(StringID("D"), StringID("A"), weightDA)
+		g.AddEdge(StringID("B"), StringID("C"), 1)
+		g.AddEdge(StringID("A"), StringID("B"), 1)
+		g.AddEdge(StringID("A"), StringID("C"), 5)
+		return g
+	}
+
+	// a square with four tied edges has four minimum spanning trees
+	expected := []string{"A -- 1.000 -→ B\n", "B -- 1.000 -→ C\n", "C -- 1.000 -→ D\n"}
+	for i := 0; i < 20; i++ {
+		tree, total, unique, err := MinimumSpanningTreeUnique(build(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rs := edgeStrings(tree); !reflect.DeepEqual(rs, expected) || total != 3 {
+			t.Fatalf("Expected %v but %v %f", expected, rs, total)
+		}
+		if unique {
+			t.Fatal("Expected the tree not to be unique")
+		}
+	}
+
+	tree, total, unique, err := MinimumSpanningTreeUnique(build(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs := edgeStrings(tree); !reflect.DeepEqual(rs, expected) || total != 3 || !unique {
+		t.Fatalf("Expected the unique tree %v but %v %f %v", expected, rs, total, unique)
+	}
+
+	// both directions of the same pair are one edge
+	g := NewGraph()
+	g.AddNode(NewNode("X", nil))
+	g.AddNode(NewNode("Y", nil))
+	g.AddEdge(StringID("Y"), StringID("X"), 1)
+	g.AddEdge(StringID("X"), StringID("Y"), 1)
+	tree, _, unique, err = MinimumSpanningTreeUnique(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs := edgeStrings(tree); !reflect.DeepEqual(rs, []string{"X -- 1.000 -→ Y\n"}) || !unique {
+		t.Fatalf("Expected the unique tree [X→Y] but %v %v", rs, unique)
+	}
+}
@@ -0,0 +1,41 @@
+package goraph
+
+// Option configures a graph created by NewGraph or by one of
+// the loaders, such as NewGraphFromJSON.
+type Option func(*graph)
+
+// WithIntIDs makes the loaders parse node IDs as IntID instead of
+// StringID, returning error for IDs that are not integers.
+func WithIntIDs() Option {
+	return func(g *graph) {
+		g.intIDs = true
+	}
+}
+
+// WithMaxNodes limits the graph to n nodes. AddNode returns false and
+// the loaders return ErrCapacityExceeded once the limit is reached.
+// n <= 0 means no limit.
+func WithMaxNodes(n int) Option {
+	return func(g *graph) {
+		g.maxNodes = n
+	}
+}
+
+// WithMaxEdges limits the graph to m edges. Adding a new edge
+// past the limit returns ErrCapacityExceeded, while updating
+// the weight of an existing edge is still allowed.
+// m <= 0 means no limit.
+func WithMaxEdges(m int) Option {
+	return func(g *graph) {
+		g.maxEdges = m
+	}
+}
+
+// WithIDValidator makes the loaders check each node ID with fn before
+// adding the node. The load fails with an error naming the first ID
+// for which fn returns error, wrapping that error.
+func WithIDValidator(fn func(id string) error) Option {
+	return func(g *graph) {
+		g.validateID = fn
+	}
+}
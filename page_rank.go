@@ -0,0 +1,109 @@
+package goraph
+
+import (
+	"fmt"
+	"math"
+)
+
+// PageRank computes the PageRank of every node with power iteration.
+// Each node passes its rank to its children in proportion to the
+// outgoing edge weights. The rank of dangling nodes (no outgoing edges)
+// is redistributed uniformly over all nodes. The iteration stops when
+// the L1 change between two rounds drops below tol, or after maxIter
+// rounds. The damping factor must be in (0, 1), usually 0.85, and tol
+// must not be negative.
+// (https://en.wikipedia.org/wiki/PageRank)
+//
+//	 0. PageRank(G, d)
+//	 1.
+//	 2. 	for each vertex v in G:
+//	 3. 		rank[v] = 1 / |V|
+//	 4.
+//	 5. 	repeat until converged:
+//	 6.
+//	 7. 		dangling = sum of rank[u] for u without children
+//	 8.
+//	 9. 		for each vertex v in G:
+//	10. 			next[v] = (1 - d) / |V| + d * dangling / |V|
+//	11.
+//	12. 		for each edge (u, v):
+//	13. 			next[v] += d * rank[u] * weight(u, v) / outWeight(u)
+//	14.
+//	15. 		rank = next
+//
+func PageRank(g Graph, damping float64, tol float64, maxIter int) (map[ID]float64, error) {
+	if math.IsNaN(damping) || damping <= 0 || damping >= 1 {
+		return nil, fmt.Errorf("damping must be in (0, 1) but %f", damping)
+	}
+	if math.IsNaN(tol) || tol < 0 {
+		return nil, fmt.Errorf("tol must not be negative but %f", tol)
+	}
+	if maxIter < 1 {
+		return nil, fmt.Errorf("maxIter must be positive but %d", maxIter)
+	}
+
+	nodes := g.Nodes()
+	n := float64(len(nodes))
+	rank := make(map[ID]float64)
+	if len(nodes) == 0 {
+		return rank, nil
+	}
+
+	// outWeight is the total weight of outgoing edges, and
+	// children the weight to each child.
+	outWeight := make(map[ID]float64)
+	children := make(map[ID]map[ID]float64)
+	for id := range nodes {
+		rank[id] = 1.0 / n
+
+		cmap, err := g.ChildNodesOf(id)
+		if err != nil {
+			return nil, err
+		}
+		children[id] = make(map[ID]float64)
+		for c := range cmap {
+			weight, err := g.EdgeWeight(id, c)
+			if err != nil {
+				return nil, err
+			}
+			if weight < 0 {
+				return nil, fmt.Errorf("weight from %s to %s must not be negative but %f", id, c, weight)
+			}
+			children[id][c] = weight
+			outWeight[id] += weight
+		}
+	}
+
+	for i := 0; i < maxIter; i++ {
+		dangling := 0.0
+		for id := range nodes {
+			if outWeight[id] == 0 {
+				dangling += rank[id]
+			}
+		}
+
+		next := make(map[ID]float64)
+		for id := range nodes {
+			next[id] = (1-damping)/n + damping*dangling/n
+		}
+		for u, cmap := range children {
+			if outWeight[u] == 0 {
+				continue
+			}
+			for v, weight := range cmap {
+				next[v] += damping * rank[u] * weight / outWeight[u]
+			}
+		}
+
+		diff := 0.0
+		for id := range nodes {
+			diff += math.Abs(next[id] - rank[id])
+		}
+		rank = next
+		if diff < tol {
+			break
+		}
+	}
+
+	return rank, nil
+}
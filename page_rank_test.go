@@ -0,0 +1,83 @@
+package goraph
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPageRank(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C"} {
+		g.AddNode(NewNode(id, make(map[string]string)))
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("A"), StringID("C"), 1)
+	g.AddEdge(StringID("B"), StringID("C"), 1)
+	g.AddEdge(StringID("C"), StringID("A"), 1)
+
+	// solved from the linear system
+	// A = 0.05 + 0.85 * C
+	// B = 0.05 + 0.85 * A / 2
+	// C = 0.05 + 0.85 * (A / 2 + B)
+	rank, err := PageRank(g, 0.85, 1e-12, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, expected := range map[string]float64{
+		"A": 0.387790,
+		"B": 0.214811,
+		"C": 0.397400,
+	} {
+		if math.Abs(rank[StringID(id)]-expected) > 1e-6 {
+			t.Errorf("%s | Expected %f but %f", id, expected, rank[StringID(id)])
+		}
+	}
+}
+
+func TestPageRank_dangling(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D"} {
+		g.AddNode(NewNode(id, make(map[string]string)))
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 3)
+	g.AddEdge(StringID("A"), StringID("C"), 1)
+	g.AddEdge(StringID("B"), StringID("C"), 1)
+	g.AddEdge(StringID("C"), StringID("A"), 1)
+	g.AddEdge(StringID("C"), StringID("D"), 1)
+
+	rank, err := PageRank(g, 0.85, 1e-12, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0.0
+	for _, v := range rank {
+		total += v
+	}
+	if math.Abs(total-1.0) > 1e-9 {
+		t.Errorf("Expected ranks to sum to 1 but %f", total)
+	}
+	for id, expected := range map[string]float64{
+		"A": 0.223421,
+		"B": 0.227408,
+		"C": 0.325750,
+		"D": 0.223421,
+	} {
+		if math.Abs(rank[StringID(id)]-expected) > 1e-6 {
+			t.Errorf("%s | Expected %f but %f", id, expected, rank[StringID(id)])
+		}
+	}
+}
+
+func TestPageRank_damping(t *testing.T) {
+	g := NewGraph()
+	for _, d := range []float64{0, 1, -0.5, 1.5, math.NaN()} {
+		if _, err := PageRank(g, d, 1e-6, 100); err == nil {
+			t.Errorf("Expected an error for damping %f", d)
+		}
+	}
+	for _, tol := range []float64{-1e-6, math.NaN()} {
+		if _, err := PageRank(g, 0.85, tol, 100); err == nil {
+			t.Errorf("Expected an error for tol %f", tol)
+		}
+	}
+}
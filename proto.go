@@ -0,0 +1,256 @@
+package goraph
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Protocol buffer wire types.
+// (https://protobuf.dev/programming-guides/encoding/)
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+// errProtoTruncated is returned when a message ends in the middle of a field.
+var errProtoTruncated = errors.New("invalid protobuf: unexpected end of data")
+
+func (g *graph) MarshalProto() ([]byte, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var buf []byte
+	buf = appendProtoString(buf, 1, g.id)
+	for _, id := range sortedIDs(g.nodes) {
+		var nbuf []byte
+		nbuf = appendProtoString(nbuf, 1, id.String())
+		props := g.nodes[id].Props()
+		keys := make([]string, 0, len(props))
+		for k := range props {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			var ebuf []byte
+			ebuf = appendProtoString(ebuf, 1, k)
+			ebuf = appendProtoString(ebuf, 2, props[k])
+			nbuf = appendProtoBytes(nbuf, 2, ebuf)
+		}
+		if weight := nodeWeight(g.nodes[id]); weight != 0 {
+			nbuf = appendProtoDouble(nbuf, 3, weight)
+		}
+		if n, ok := g.nodes[id].(*node); ok && n.hasTypedProps() {
+			b, err := json.Marshal(n.TypedProps())
+			if err != nil {
+				return nil, err
+			}
+			nbuf = appendProtoBytes(nbuf, 4, b)
+		}
+		buf = appendProtoBytes(buf, 2, nbuf)
+	}
+	for _, id1 := range sortedIDs(g.nodes) {
+		tmap := g.nodeChildren[id1]
+		ids := make([]ID, 0, len(tmap))
+		for id2 := range tmap {
+			ids = append(ids, id2)
+		}
+		sort.Sort(idSlice(ids))
+		for _, id2 := range ids {
+			var ebuf []byte
+			ebuf = appendProtoString(ebuf, 1, id1.String())
+			ebuf = appendProtoString(ebuf, 2, id2.String())
+			ebuf = appendProtoDouble(ebuf, 3, tmap[id2])
+			buf = appendProtoBytes(buf, 3, ebuf)
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalProto returns a new Graph from the protocol buffer
+// encoding written by MarshalProto. The schema is in goraph.proto.
+// Edges may reference nodes that are not listed, which are then
+// added without properties.
+func UnmarshalProto(data []byte, opts ...Option) (Graph, error) {
+	g := newGraph(opts...)
+	err := walkProto(data, func(num int, typ int, v uint64, b []byte) error {
+		switch {
+		case num == 1 && typ == protoBytes:
+			g.id = string(b)
+		case num == 2 && typ == protoBytes:
+			return unmarshalProtoNode(g, b)
+		case num == 3 && typ == protoBytes:
+			return unmarshalProtoEdge(g, b)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func unmarshalProtoNode(g *graph, data []byte) error {
+	id := ""
+	props := make(map[string]string)
+	weight := 0.0
+	var typed map[string]interface{}
+	err := walkProto(data, func(num int, typ int, v uint64, b []byte) error {
+		switch {
+		case num == 1 && typ == protoBytes:
+			id = string(b)
+		case num == 3 && typ == protoFixed64:
+			weight = math.Float64frombits(v)
+		case num == 4 && typ == protoBytes:
+			dec := json.NewDecoder(bytes.NewReader(b))
+			dec.UseNumber()
+			if err := dec.Decode(&typed); err != nil {
+				return err
+			}
+			return convertJSONNumbers(typed)
+		case num == 2 && typ == protoBytes:
+			k, val := "", ""
+			err := walkProto(b, func(num int, typ int, v uint64, b []byte) error {
+				switch {
+				case num == 1 && typ == protoBytes:
+					k = string(b)
+				case num == 2 && typ == protoBytes:
+					val = string(b)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			props[k] = val
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	nd, err := g.loadNode(id)
+	if err != nil {
+		return err
+	}
+	n, ok := nd.(*node)
+	if !ok {
+		return fmt.Errorf("properties of %s cannot be set", id)
+	}
+	if typed != nil {
+		for k, v := range typed {
+			n.setTypedProp(k, v)
+		}
+	} else {
+		for k, v := range props {
+			n.SetProp(k, v)
+		}
+	}
+	if weight != 0 {
+		n.setWeight(weight)
+	}
+	return nil
+}
+
+func unmarshalProtoEdge(g *graph, data []byte) error {
+	src, tgt := "", ""
+	weight := 0.0
+	err := walkProto(data, func(num int, typ int, v uint64, b []byte) error {
+		switch {
+		case num == 1 && typ == protoBytes:
+			src = string(b)
+		case num == 2 && typ == protoBytes:
+			tgt = string(b)
+		case num == 3 && typ == protoFixed64:
+			weight = math.Float64frombits(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	nd1, err := g.loadNode(src)
+	if err != nil {
+		return err
+	}
+	nd2, err := g.loadNode(tgt)
+	if err != nil {
+		return err
+	}
+	return g.ReplaceEdge(nd1.ID(), nd2.ID(), weight)
+}
+
+// walkProto calls fn for each field of the message in data. Varint and
+// fixed values are passed in v, and length-delimited values in b.
+// Unknown fields are skipped by the callers.
+func walkProto(data []byte, fn func(num int, typ int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errProtoTruncated
+		}
+		data = data[n:]
+		num, typ := int(key>>3), int(key&7)
+
+		var v uint64
+		var b []byte
+		switch typ {
+		case protoVarint:
+			v, n = binary.Uvarint(data)
+			if n <= 0 {
+				return errProtoTruncated
+			}
+			data = data[n:]
+		case protoFixed64:
+			if len(data) < 8 {
+				return errProtoTruncated
+			}
+			v = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case protoBytes:
+			l, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < l {
+				return errProtoTruncated
+			}
+			b = data[n : n+int(l)]
+			data = data[n+int(l):]
+		case protoFixed32:
+			if len(data) < 4 {
+				return errProtoTruncated
+			}
+			v = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		default:
+			return fmt.Errorf("invalid protobuf: unsupported wire type %d of field %d", typ, num)
+		}
+		if err := fn(num, typ, v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendProtoKey(buf []byte, num int, typ int) []byte {
+	return binary.AppendUvarint(buf, uint64(num)<<3|uint64(typ))
+}
+
+func appendProtoBytes(buf []byte, num int, b []byte) []byte {
+	buf = appendProtoKey(buf, num, protoBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+func appendProtoString(buf []byte, num int, s string) []byte {
+	return appendProtoBytes(buf, num, []byte(s))
+}
+
+func appendProtoDouble(buf []byte, num int, f float64) []byte {
+	buf = appendProtoKey(buf, num, protoFixed64)
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(f))
+}
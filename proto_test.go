@@ -0,0 +1,74 @@
+package goraph
+
+import (
+	"os"
+	"testing"
+)
+
+func TestGraph_MarshalProto(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.AddNode(NewNode("lonely", map[string]string{"color": "red", "label": "x=1"}))
+	nd, _ := g.Node(StringID("S"))
+	nd.(PropNode).SetProp("kind", "source")
+	g.ReplaceEdge(StringID("A"), StringID("B"), 0.125)
+	g.AddNode(NewWeightedNode("heavy", 2.5, map[string]string{"color": "blue"}))
+	g.AddNode(NewTypedNode("typed", map[string]interface{}{"count": 3, "ratio": 0.5, "ok": true, "name": "x"}))
+
+	data, err := g.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2, err := UnmarshalProto(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g2.ID() != g.ID() {
+		t.Fatalf("Expected graph ID %s but %s", g.ID(), g2.ID())
+	}
+	if !Equal(g, g2) {
+		t.Fatalf("Expected equal graphs but\n%s\n%s", g, g2)
+	}
+	nd, _ = g2.Node(StringID("heavy"))
+	if w := nd.(WeightedNode).Weight(); w != 2.5 {
+		t.Fatalf("Expected weight 2.5 but %v", w)
+	}
+	nd, _ = g2.Node(StringID("typed"))
+	typed := nd.(TypedNode).TypedProps()
+	if typed["count"] != 3 || typed["ratio"] != 0.5 || typed["ok"] != true || typed["name"] != "x" {
+		t.Fatalf("Expected typed properties but %v", typed)
+	}
+
+	if _, err := UnmarshalProto(data[:len(data)-1]); err == nil {
+		t.Fatal("Expected error from truncated data")
+	}
+}
+
+func TestGraph_MarshalProto_wire(t *testing.T) {
+	g := newGraph()
+	g.id = "g"
+	g.AddNode(NewNode("A", map[string]string{"k": "v"}))
+	g.AddNode(NewNode("B", nil))
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+
+	data, err := g.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{
+		0x0a, 0x01, 'g', // id
+		0x12, 0x0b, 0x0a, 0x01, 'A', 0x12, 0x06, 0x0a, 0x01, 'k', 0x12, 0x01, 'v', // node A
+		0x12, 0x03, 0x0a, 0x01, 'B', // node B
+		0x1a, 0x0f, 0x0a, 0x01, 'A', 0x12, 0x01, 'B', 0x19, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, // edge
+	}
+	if string(data) != string(expected) {
+		t.Fatalf("Expected % x but % x", expected, data)
+	}
+}
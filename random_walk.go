@@ -0,0 +1,72 @@
+package goraph
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// RandomWalk walks steps edges from start, and returns the visited
+// nodes including start. At each step it moves to a child chosen with
+// probability proportional to the weight of the edge to it, so edges
+// with zero weight are never taken. The walk stops early at a node
+// without outgoing edges of positive weight, and returns the partial
+// walk. Children are considered in sorted order, so the same seed of
+// rng always gives the same walk. It returns error for negative weights.
+// (https://en.wikipedia.org/wiki/Random_walk)
+func RandomWalk(g Graph, start ID, steps int, rng *rand.Rand) ([]ID, error) {
+	if steps < 0 {
+		return nil, fmt.Errorf("steps must not be negative but %d", steps)
+	}
+	if rng == nil {
+		return nil, errors.New("rng must not be nil")
+	}
+	if _, err := g.Node(start); err != nil {
+		return nil, err
+	}
+
+	rs := []ID{start}
+	u := start
+	for i := 0; i < steps; i++ {
+		cmap, err := g.ChildNodesOf(u)
+		if err != nil {
+			return nil, err
+		}
+		ids := sortedIDs(cmap)
+		weights := make([]float64, len(ids))
+		total := 0.0
+		for j, v := range ids {
+			weight, err := g.EdgeWeight(u, v)
+			if err != nil {
+				return nil, err
+			}
+			if weight < 0 {
+				return nil, fmt.Errorf("weight from %s to %s must not be negative but %f", u, v, weight)
+			}
+			weights[j] = weight
+			total += weight
+		}
+		if total == 0 {
+			break
+		}
+
+		// on a rounding error, the last child
+		// with positive weight is picked
+		var next ID
+		x := rng.Float64() * total
+		for j, weight := range weights {
+			if weight == 0 {
+				continue
+			}
+			next = ids[j]
+			if x < weight {
+				break
+			}
+			x -= weight
+		}
+
+		rs = append(rs, next)
+		u = next
+	}
+	return rs, nil
+}
@@ -0,0 +1,60 @@
+package goraph
+
+import (
+	"math/rand"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestRandomWalk(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	walk1, err := RandomWalk(g, StringID("S"), 20, rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	walk2, err := RandomWalk(g, StringID("S"), 20, rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(walk1) != 21 {
+		t.Fatalf("Expected 21 nodes but %v", walk1)
+	}
+	if !reflect.DeepEqual(walk1, walk2) {
+		t.Fatalf("Expected the same walk but %v and %v", walk1, walk2)
+	}
+	for i := 1; i < len(walk1); i++ {
+		if !g.HasEdge(walk1[i-1], walk1[i]) {
+			t.Fatalf("Expected an edge from %s to %s in %v", walk1[i-1], walk1[i], walk1)
+		}
+	}
+
+	// dead end
+	g = NewGraph()
+	for _, id := range []string{"A", "B", "C"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("A"), StringID("C"), 0)
+	walk, err := RandomWalk(g, StringID("A"), 10, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ID{StringID("A"), StringID("B")}
+	if !reflect.DeepEqual(walk, expected) {
+		t.Fatalf("Expected %v but %v", expected, walk)
+	}
+
+	if _, err := RandomWalk(g, StringID("X"), 10, rand.New(rand.NewSource(1))); err == nil {
+		t.Fatal("Expected error for a missing node")
+	}
+}
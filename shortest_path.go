@@ -4,6 +4,7 @@ import (
 	"container/heap"
 	"fmt"
 	"math"
+	"sort"
 )
 
 // Dijkstra returns the shortest path using Dijkstra
@@ -346,3 +347,311 @@ func BellmanFord(g Graph, source, target ID) ([]ID, map[ID]float64, error) {
 
 	return path, distance, nil
 }
+
+// AllPairsShortestPaths returns the shortest distance between every
+// pair of nodes using Floyd-Warshall algorithm. Unreachable pairs map to
+// +Inf and the distance from a node to itself is 0. It works with
+// negative weight edges, but returns ErrNegativeCycle when there is a
+// negative-weight cycle. Time complexity is O(|V|^3).
+// (https://en.wikipedia.org/wiki/Floyd%E2%80%93Warshall_algorithm)
+//
+//	 0. FloydWarshall(G)
+//	 1.
+//	 2. 	for each vertex v in G:
+//	 3. 		for each vertex w in G:
+//	 4. 			distance[v][w] = ∞
+//	 5. 		distance[v][v] = 0
+//	 6.
+//	 7. 	for each edge (u, v):
+//	 8. 		distance[u][v] = min(distance[u][v], weight(u, v))
+//	 9.
+//	10. 	for each vertex k in G:
+//	11. 		for each vertex i in G:
+//	12. 			for each vertex j in G:
+//	13. 				alt = distance[i][k] + distance[k][j]
+//	14. 				if distance[i][j] > alt:
+//	15. 					distance[i][j] = alt
+//	16.
+//	17. 	for each vertex v in G:
+//	18. 		if distance[v][v] < 0:
+//	19. 			there is a negative-weight cycle
+//
+func AllPairsShortestPaths(g Graph) (map[ID]map[ID]float64, error) {
+	nodes := g.Nodes()
+
+	// distance[v][w] = ∞
+	// distance[v][v] = 0
+	distance := make(map[ID]map[ID]float64)
+	for v := range nodes {
+		distance[v] = make(map[ID]float64)
+		for w := range nodes {
+			distance[v][w] = math.Inf(1)
+		}
+		distance[v][v] = 0.0
+	}
+
+	// for each edge (u, v):
+	for u := range nodes {
+		cmap, err := g.ChildNodesOf(u)
+		if err != nil {
+			return nil, err
+		}
+		for v := range cmap {
+			weight, err := g.EdgeWeight(u, v)
+			if err != nil {
+				return nil, err
+			}
+			// distance[u][v] = min(distance[u][v], weight(u, v))
+			if weight < distance[u][v] {
+				distance[u][v] = weight
+			}
+		}
+	}
+
+	for k := range nodes {
+		for i := range nodes {
+			if math.IsInf(distance[i][k], 1) {
+				continue
+			}
+			for j := range nodes {
+				// alt = distance[i][k] + distance[k][j]
+				alt := distance[i][k] + distance[k][j]
+
+				// if distance[i][j] > alt:
+				if distance[i][j] > alt {
+					distance[i][j] = alt
+				}
+			}
+		}
+	}
+
+	for v := range nodes {
+		// if distance[v][v] < 0:
+		if distance[v][v] < 0 {
+			return nil, ErrNegativeCycle
+		}
+	}
+
+	return distance, nil
+}
+
+// AStar returns the shortest path from source to target and its total
+// weight, using A* search with a min-priority queue. h estimates the cost
+// from a node to the target, and must never overestimate it (admissible)
+// for the result to be the shortest path. A node that was already
+// visited is reopened when a shorter path to it is found, so h does not
+// need to be consistent. When h returns 0 for every node, AStar behaves
+// like Dijkstra. This algorithm does not work with
+// negative weight edges. It returns ErrNoPath when target cannot be
+// reached.
+// (https://en.wikipedia.org/wiki/A*_search_algorithm)
+//
+//	 0. AStar(G, source, target, h)
+//	 1.
+//	 2. 	let Q be a priority queue
+//	 3. 	distance[source] = 0
+//	 4. 	Q.add_with_priority(source, h(source))
+//	 5.
+//	 6. 	while Q is not empty:
+//	 7.
+//	 8. 		u = Q.extract_min()
+//	 9. 		if u == target:
+//	10. 			return path to u
+//	11.
+//	12. 		for each child vertex v of u:
+//	13.
+//	14. 			alt = distance[u] + weight(u, v)
+//	15. 			if v has no distance or distance[v] > alt:
+//	16. 				distance[v] = alt
+//	17. 				prev[v] = u
+//	18. 				Q.add_with_priority(v, alt + h(v))
+//	19.
+//	20. 	there is no path
+//
+func AStar(g Graph, source, target ID, h func(n Node) float64) ([]ID, float64, error) {
+	path, distance, _, err := aStar(g, source, target, h, nil, nil)
+	return path, distance, err
+}
+
+// ShortestPathFunc returns the shortest path from source to target and
+// its distance like Dijkstra, where the weight of each edge is the one
+// returned by weight from the endpoint nodes and the stored weight.
+// It returns error if weight returns a negative value, and ErrNoPath
+// when target cannot be reached.
+func ShortestPathFunc(g Graph, source, target ID, weight WeightFunc) ([]ID, float64, error) {
+	path, distance, _, err := aStar(g, source, target, nil, weight, nil)
+	return path, distance, err
+}
+
+// aStar implements AStar, and also returns the number
+// of nodes that were visited before reaching the target.
+// Edge weights are transformed by weight unless it is nil.
+// Edges for which skip returns true are ignored.
+func aStar(g Graph, source, target ID, h func(n Node) float64, weightFn WeightFunc, skip func(u, v ID) bool) ([]ID, float64, int, error) {
+	if h == nil {
+		h = func(n Node) float64 { return 0 }
+	}
+	src, err := g.Node(source)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if _, err := g.Node(target); err != nil {
+		return nil, 0, 0, err
+	}
+
+	// let Q be a priority queue
+	minHeap := &nodeDistanceHeap{}
+
+	// distance[source] = 0
+	distance := make(map[ID]float64)
+	distance[source] = 0.0
+
+	// Q.add_with_priority(source, h(source))
+	heap.Push(minHeap, nodeDistance{id: source, distance: h(src)})
+
+	prev := make(map[ID]ID)
+	visited := make(map[ID]bool)
+
+	// while Q is not empty:
+	for minHeap.Len() != 0 {
+
+		// u = Q.extract_min()
+		u := heap.Pop(minHeap).(nodeDistance)
+
+		// Q may hold stale entries of visited nodes
+		// instead of decreasing their priority.
+		if visited[u.id] {
+			continue
+		}
+		visited[u.id] = true
+
+		// if u == target:
+		if u.id == target {
+			// return path to u
+			path := []ID{target}
+			for v := target; v != source; {
+				v = prev[v]
+				path = append([]ID{v}, path...)
+			}
+			return path, distance[target], len(visited), nil
+		}
+
+		// for each child vertex v of u:
+		cmap, err := g.ChildNodesOf(u.id)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		for v, nd := range cmap {
+			if skip != nil && skip(u.id, v) {
+				continue
+			}
+
+			// alt = distance[u] + weight(u, v)
+			var weight float64
+			if weightFn != nil {
+				weight, err = g.EffectiveWeight(u.id, v, weightFn)
+			} else {
+				weight, err = g.EdgeWeight(u.id, v)
+			}
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			if weight < 0 {
+				return nil, 0, 0, fmt.Errorf("weight from %s to %s must not be negative but %f", u.id, v, weight)
+			}
+			alt := distance[u.id] + weight
+
+			// if v has no distance or distance[v] > alt:
+			if d, ok := distance[v]; !ok || d > alt {
+				// distance[v] = alt
+				distance[v] = alt
+
+				// prev[v] = u
+				prev[v] = u.id
+
+				// reopen v if it was visited through a longer path,
+				// which an inconsistent h allows
+				delete(visited, v)
+
+				// Q.add_with_priority(v, alt + h(v))
+				heap.Push(minHeap, nodeDistance{id: v, distance: alt + h(nd)})
+			}
+		}
+	}
+
+	// there is no path
+	return nil, 0, len(visited), ErrNoPath
+}
+
+// ClosestNodes returns the n nodes with the smallest distance from
+// from, excluding from itself, in increasing order of distance, and
+// their distances. Nodes at the same distance are ordered by ID.
+// Dijkstra stops once n nodes are settled, so only the edges around
+// the nearest nodes are visited. Fewer than n nodes are returned if
+// fewer are reachable. It returns error if a visited edge has
+// a negative weight.
+func (g *graph) ClosestNodes(from ID, n int) ([]ID, []float64, error) {
+	if _, err := g.Node(from); err != nil {
+		return nil, nil, err
+	}
+	if n <= 0 {
+		return nil, nil, nil
+	}
+
+	distance := map[ID]float64{from: 0}
+	settled := make(map[ID]bool)
+	minHeap := &nodeDistanceHeap{{id: from, distance: 0}}
+
+	var found []nodeDistance
+	for minHeap.Len() != 0 {
+		u := heap.Pop(minHeap).(nodeDistance)
+		if settled[u.id] {
+			// stale entry left by an earlier decrease
+			continue
+		}
+		if len(found) >= n && u.distance > found[len(found)-1].distance {
+			// settled n nodes and every node tied with the last one
+			break
+		}
+		settled[u.id] = true
+		if u.id != from {
+			found = append(found, u)
+		}
+
+		cmap, err := g.ChildNodesOf(u.id)
+		if err != nil {
+			return nil, nil, err
+		}
+		for v := range cmap {
+			weight, err := g.EdgeWeight(u.id, v)
+			if err != nil {
+				return nil, nil, err
+			}
+			if weight < 0 {
+				return nil, nil, fmt.Errorf("weight from %s to %s must not be negative but %f", u.id, v, weight)
+			}
+			alt := u.distance + weight
+			if d, ok := distance[v]; !settled[v] && (!ok || alt < d) {
+				distance[v] = alt
+				heap.Push(minHeap, nodeDistance{id: v, distance: alt})
+			}
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].distance != found[j].distance {
+			return found[i].distance < found[j].distance
+		}
+		return lessID(found[i].id, found[j].id)
+	})
+	if len(found) > n {
+		found = found[:n]
+	}
+	ids := make([]ID, len(found))
+	ds := make([]float64, len(found))
+	for i, nd := range found {
+		ids[i] = nd.id
+		ds[i] = nd.distance
+	}
+	return ids, ds, nil
+}
@@ -1,8 +1,12 @@
 package goraph
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -214,3 +218,349 @@ func TestGraph_BellmanFord_12(t *testing.T) {
 		t.Errorf("Expected nil, nil but %v, %v", path, distance)
 	}
 }
+
+func TestAllPairsShortestPaths(t *testing.T) {
+	for _, elem := range []struct {
+		name     string
+		source   string
+		target   string
+		distance float64
+	}{
+		{"graph_03", "S", "T", 44.0},
+		{"graph_04", "A", "E", 20.0},
+		{"graph_09", "E", "A", 22.0},
+		{"graph_10", "S", "T", 68.0},
+		{"graph_11", "S", "T", -2.0},
+	} {
+		f, err := os.Open("testdata/graph.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		g, err := NewGraphFromJSON(f, elem.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		distance, err := AllPairsShortestPaths(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := distance[StringID(elem.source)][StringID(elem.target)]; v != elem.distance {
+			t.Errorf("%s | Expected %f but %f", elem.name, elem.distance, v)
+		}
+		for id := range g.Nodes() {
+			if distance[id][id] != 0.0 {
+				t.Errorf("%s | Expected 0 from %s to itself but %f", elem.name, id, distance[id][id])
+			}
+		}
+	}
+
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	distance, err := AllPairsShortestPaths(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(distance[StringID("A")][StringID("B")], 1) {
+		t.Errorf("Expected +Inf from A to B but %f", distance[StringID("A")][StringID("B")])
+	}
+}
+
+func TestAllPairsShortestPaths_12(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_12")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AllPairsShortestPaths(g); err != ErrNegativeCycle {
+		t.Fatalf("Expected ErrNegativeCycle but %v", err)
+	}
+}
+
+func TestAStar(t *testing.T) {
+	for _, elem := range []struct {
+		name     string
+		source   string
+		target   string
+		path     string
+		distance float64
+	}{
+		{"graph_03", "S", "T", "S → B → E → F → T", 44.0},
+		{"graph_04", "A", "E", "A → C → F → E", 20.0},
+		{"graph_09", "E", "A", "E → F → C → B → A", 22.0},
+		{"graph_10", "S", "T", "S → A → B → D → E → T", 68.0},
+	} {
+		f, err := os.Open("testdata/graph.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		g, err := NewGraphFromJSON(f, elem.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path, distance, err := AStar(g, StringID(elem.source), StringID(elem.target), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts := []string{}
+		for _, v := range path {
+			ts = append(ts, v.String())
+		}
+		if strings.Join(ts, " → ") != elem.path || distance != elem.distance {
+			t.Errorf("%s | Expected %s(%.2f) but %s(%.2f)", elem.name, elem.path, elem.distance, strings.Join(ts, " → "), distance)
+		}
+	}
+}
+
+func TestAStar_heuristic(t *testing.T) {
+	// 10x10 grid with unit weights, and the coordinates in props
+	g := NewGraph()
+	id := func(x, y int) ID { return StringID(fmt.Sprintf("%d,%d", x, y)) }
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			g.AddNode(NewNode(id(x, y).String(), map[string]string{"x": strconv.Itoa(x), "y": strconv.Itoa(y)}))
+		}
+	}
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			if x < 9 {
+				g.AddEdge(id(x, y), id(x+1, y), 1)
+				g.AddEdge(id(x+1, y), id(x, y), 1)
+			}
+			if y < 9 {
+				g.AddEdge(id(x, y), id(x, y+1), 1)
+				g.AddEdge(id(x, y+1), id(x, y), 1)
+			}
+		}
+	}
+	manhattan := func(n Node) float64 {
+		x, _ := strconv.Atoi(n.Props()["x"])
+		y, _ := strconv.Atoi(n.Props()["y"])
+		return math.Abs(float64(9-x)) + math.Abs(float64(0-y))
+	}
+
+	path1, distance1, visited1, err := aStar(g, id(0, 0), id(9, 0), manhattan, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path2, distance2, visited2, err := aStar(g, id(0, 0), id(9, 0), nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if distance1 != 9 || distance2 != 9 || len(path1) != 10 || len(path2) != 10 {
+		t.Fatalf("Expected 9 but %v(%.2f) and %v(%.2f)", path1, distance1, path2, distance2)
+	}
+	if visited1 >= visited2 {
+		t.Fatalf("Expected fewer visited nodes with the heuristic but %d and %d", visited1, visited2)
+	}
+}
+
+func TestAStar_inconsistent(t *testing.T) {
+	// h is admissible but not consistent, so B is first
+	// visited through the longer path S → B
+	g := NewGraph()
+	for _, id := range []string{"S", "A", "B", "T"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	g.AddEdge(StringID("S"), StringID("A"), 1)
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("S"), StringID("B"), 3)
+	g.AddEdge(StringID("B"), StringID("T"), 3)
+	h := func(n Node) float64 {
+		if n.ID() == StringID("A") {
+			return 4
+		}
+		return 0
+	}
+
+	path, distance, err := AStar(g, StringID("S"), StringID("T"), h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(path) != "[S A B T]" || distance != 5 {
+		t.Fatalf("Expected [S A B T](5.00) but %v(%.2f)", path, distance)
+	}
+}
+
+func TestAStar_errors(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := AStar(g, StringID("A"), StringID("D"), nil); err != ErrNoPath {
+		t.Fatalf("Expected ErrNoPath but %v", err)
+	}
+	if _, _, err := AStar(g, StringID("X"), StringID("D"), nil); err == nil {
+		t.Fatal("Expected an error for unknown source")
+	}
+	if _, _, err := AStar(g, StringID("A"), StringID("X"), nil); err == nil {
+		t.Fatal("Expected an error for unknown target")
+	}
+	path, distance, err := AStar(g, StringID("A"), StringID("A"), nil)
+	if err != nil || len(path) != 1 || distance != 0 {
+		t.Fatalf("Expected [A] but %v %f %v", path, distance, err)
+	}
+}
+
+func TestShortestPathFunc(t *testing.T) {
+	g := NewGraph()
+	g.AddNode(NewNode("S", nil))
+	g.AddNode(NewNode("A", map[string]string{"multiplier": "10"}))
+	g.AddNode(NewNode("B", nil))
+	g.AddNode(NewNode("T", nil))
+	g.AddEdge(StringID("S"), StringID("A"), 1)
+	g.AddEdge(StringID("A"), StringID("T"), 1)
+	g.AddEdge(StringID("S"), StringID("B"), 3)
+	g.AddEdge(StringID("B"), StringID("T"), 3)
+
+	// entering a node costs its multiplier times the stored weight
+	multiplier := func(src, tgt Node, stored float64) float64 {
+		if v, ok := tgt.Props()["multiplier"]; ok {
+			m, _ := strconv.ParseFloat(v, 64)
+			return m * stored
+		}
+		return stored
+	}
+
+	if v, err := g.EffectiveWeight(StringID("S"), StringID("A"), multiplier); err != nil || v != 10 {
+		t.Fatalf("Expected 10 but %v %v", v, err)
+	}
+	if v, _ := g.EdgeWeight(StringID("S"), StringID("A")); v != 1 {
+		t.Fatalf("Expected the stored weight 1 but %f", v)
+	}
+	if _, err := g.EffectiveWeight(StringID("S"), StringID("X"), multiplier); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+	if _, err := g.EffectiveWeight(StringID("S"), StringID("T"), multiplier); !errors.Is(err, ErrEdgeNotFound) {
+		t.Fatalf("Expected ErrEdgeNotFound but %v", err)
+	}
+
+	path, distance, err := ShortestPathFunc(g, StringID("S"), StringID("T"), multiplier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(path, []ID{StringID("S"), StringID("B"), StringID("T")}) || distance != 6 {
+		t.Fatalf("Expected [S B T] with 6 but %v %f", path, distance)
+	}
+	path, distance, err = ShortestPathFunc(g, StringID("S"), StringID("T"), func(src, tgt Node, stored float64) float64 { return stored })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(path, []ID{StringID("S"), StringID("A"), StringID("T")}) || distance != 2 {
+		t.Fatalf("Expected [S A T] with 2 but %v %f", path, distance)
+	}
+
+	negative := func(src, tgt Node, stored float64) float64 { return -stored }
+	if _, _, err := ShortestPathFunc(g, StringID("S"), StringID("T"), negative); err == nil {
+		t.Fatal("Expected error for a negative weight")
+	}
+}
+
+func TestGraph_ClosestNodes_03(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, distance, err := Dijkstra(g, StringID("S"), StringID("T"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all, ds, err := g.ClosestNodes(StringID("S"), g.NodeCount())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != g.NodeCount()-1 {
+		t.Fatalf("Expected %d nodes but %v", g.NodeCount()-1, all)
+	}
+	for i, id := range all {
+		if id == StringID("S") {
+			t.Fatalf("Expected the source to be excluded but %v", all)
+		}
+		if ds[i] != distance[id] {
+			t.Fatalf("Expected %s at %f but %f", id, distance[id], ds[i])
+		}
+		if i > 0 && ds[i] < ds[i-1] {
+			t.Fatalf("Expected increasing distances but %v", ds)
+		}
+	}
+
+	// a prefix of the full order
+	ids, _, err := g.ClosestNodes(StringID("S"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, all[:3]) {
+		t.Fatalf("Expected %v but %v", all[:3], ids)
+	}
+}
+
+func TestGraph_ClosestNodes_earlyStop(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D", "E", "F"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	g.AddEdge(StringID("A"), StringID("C"), 2)
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("B"), StringID("D"), 10)
+	// never visited when only the two nearest nodes are needed
+	g.AddEdge(StringID("D"), StringID("E"), -1)
+
+	ids, ds, err := g.ClosestNodes(StringID("A"), 2)
+	if err != nil {
+		t.Fatalf("Expected no visit of the far edge but %v", err)
+	}
+	if !reflect.DeepEqual(ids, []ID{StringID("B"), StringID("C")}) || !reflect.DeepEqual(ds, []float64{1, 2}) {
+		t.Fatalf("Expected [B C] [1 2] but %v %v", ids, ds)
+	}
+
+	if _, _, err := g.ClosestNodes(StringID("A"), 4); err == nil {
+		t.Fatal("Expected error for the negative weight")
+	}
+
+	// F is unreachable, so fewer nodes are returned
+	g.ReplaceEdge(StringID("D"), StringID("E"), 1)
+	ids, ds, err = g.ClosestNodes(StringID("A"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []ID{StringID("B"), StringID("C"), StringID("D"), StringID("E")}) || !reflect.DeepEqual(ds, []float64{1, 2, 11, 12}) {
+		t.Fatalf("Expected [B C D E] [1 2 11 12] but %v %v", ids, ds)
+	}
+
+	// ties are ordered by ID
+	g.ReplaceEdge(StringID("A"), StringID("C"), 1)
+	ids, _, err = g.ClosestNodes(StringID("A"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []ID{StringID("B")}) {
+		t.Fatalf("Expected [B] but %v", ids)
+	}
+
+	if _, _, err := g.ClosestNodes(StringID("X"), 1); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+}
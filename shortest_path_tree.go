@@ -0,0 +1,129 @@
+package goraph
+
+import (
+	"container/heap"
+	"math"
+	"sync"
+)
+
+// SPTree is a shortest path tree from a source node, computed once with
+// Dijkstra so that the distance and path to any node can be looked up
+// without searching the graph again. It does not track changes to the
+// graph: call Invalidate after editing the graph, and build a new tree.
+// It is safe to query from multiple goroutines until invalidated.
+type SPTree struct {
+	source   ID
+	distance map[ID]float64
+	prev     map[ID]ID
+
+	mu    sync.RWMutex // guards the following
+	stale bool
+}
+
+// BuildShortestPathTree runs Dijkstra from source over the whole graph.
+// It returns error if the source does not exist or an edge has a
+// negative weight.
+func BuildShortestPathTree(g Graph, source ID) (*SPTree, error) {
+	if _, err := g.Node(source); err != nil {
+		return nil, err
+	}
+	if err := checkNonNegative(g); err != nil {
+		return nil, err
+	}
+
+	t := &SPTree{
+		source:   source,
+		distance: make(map[ID]float64),
+		prev:     make(map[ID]ID),
+	}
+
+	// a lazy Dijkstra, where Q may hold stale entries
+	// instead of decreasing priorities
+	minHeap := &nodeDistanceHeap{}
+	heap.Push(minHeap, nodeDistance{id: source, distance: 0})
+	best := map[ID]float64{source: 0}
+	for minHeap.Len() != 0 {
+		u := heap.Pop(minHeap).(nodeDistance)
+		if _, ok := t.distance[u.id]; ok {
+			continue
+		}
+		t.distance[u.id] = u.distance
+
+		cmap, err := g.ChildNodesOf(u.id)
+		if err != nil {
+			return nil, err
+		}
+		for v := range cmap {
+			if _, ok := t.distance[v]; ok {
+				continue
+			}
+			weight, err := g.EdgeWeight(u.id, v)
+			if err != nil {
+				return nil, err
+			}
+			alt := u.distance + weight
+			if d, ok := best[v]; !ok || d > alt {
+				best[v] = alt
+				t.prev[v] = u.id
+				heap.Push(minHeap, nodeDistance{id: v, distance: alt})
+			}
+		}
+	}
+	return t, nil
+}
+
+// Source returns the ID of the source node.
+func (t *SPTree) Source() ID {
+	return t.source
+}
+
+// DistanceTo returns the shortest distance from the source to the node,
+// or +Inf if the node cannot be reached.
+func (t *SPTree) DistanceTo(id ID) (float64, error) {
+	if t.Stale() {
+		return 0, ErrStale
+	}
+	if d, ok := t.distance[id]; ok {
+		return d, nil
+	}
+	return math.Inf(1), nil
+}
+
+// PathTo returns the shortest path from the source to the node,
+// in time proportional to its length. It returns ErrNoPath if
+// the node cannot be reached.
+func (t *SPTree) PathTo(id ID) ([]ID, error) {
+	if t.Stale() {
+		return nil, ErrStale
+	}
+	if _, ok := t.distance[id]; !ok {
+		return nil, ErrNoPath
+	}
+
+	path := []ID{id}
+	for v := id; v != t.source; {
+		v = t.prev[v]
+		path = append(path, v)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, nil
+}
+
+// Invalidate marks the tree as stale, so that
+// later queries return ErrStale.
+func (t *SPTree) Invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stale = true
+}
+
+// Stale returns true if Invalidate has been called.
+func (t *SPTree) Stale() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.stale
+}
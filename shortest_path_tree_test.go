@@ -0,0 +1,100 @@
+package goraph
+
+import (
+	"errors"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBuildShortestPathTree(t *testing.T) {
+	for _, elem := range []struct {
+		name   string
+		source string
+	}{{"graph_03", "S"}, {"graph_04", "A"}, {"graph_09", "E"}, {"graph_10", "S"}} {
+		name := elem.name
+		f, err := os.Open("testdata/graph.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		g, err := NewGraphFromJSON(f, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		source := StringID(elem.source)
+		tree, err := BuildShortestPathTree(g, source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for id := range g.Nodes() {
+			path, distance, err := Dijkstra(g, source, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d, err := tree.DistanceTo(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d != distance[id] {
+				t.Fatalf("%s | Expected distance %f to %s but %f", name, distance[id], id, d)
+			}
+			tpath, err := tree.PathTo(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tpath) != len(path) || tpath[0] != path[0] || tpath[len(tpath)-1] != path[len(path)-1] {
+				t.Fatalf("%s | Expected a path like %v but %v", name, path, tpath)
+			}
+			total := 0.0
+			for i := 1; i < len(tpath); i++ {
+				weight, err := g.EdgeWeight(tpath[i-1], tpath[i])
+				if err != nil {
+					t.Fatalf("%s | %v in %v", name, err, tpath)
+				}
+				total += weight
+			}
+			if total != d {
+				t.Fatalf("%s | Expected path weight %f but %f", name, d, total)
+			}
+		}
+	}
+}
+
+func TestSPTree_Invalidate(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 2)
+
+	tree, err := BuildShortestPathTree(g, StringID("A"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path, err := tree.PathTo(StringID("B")); err != nil || !reflect.DeepEqual(path, []ID{StringID("A"), StringID("B")}) {
+		t.Fatalf("Expected [A B] but %v %v", path, err)
+	}
+	if path, err := tree.PathTo(StringID("A")); err != nil || !reflect.DeepEqual(path, []ID{StringID("A")}) {
+		t.Fatalf("Expected [A] but %v %v", path, err)
+	}
+	if d, err := tree.DistanceTo(StringID("C")); err != nil || !math.IsInf(d, 1) {
+		t.Fatalf("Expected +Inf but %f %v", d, err)
+	}
+	if _, err := tree.PathTo(StringID("C")); err != ErrNoPath {
+		t.Fatalf("Expected ErrNoPath but %v", err)
+	}
+
+	tree.Invalidate()
+	if !tree.Stale() {
+		t.Fatal("Expected a stale tree")
+	}
+	if _, err := tree.DistanceTo(StringID("B")); !errors.Is(err, ErrStale) {
+		t.Fatalf("Expected ErrStale but %v", err)
+	}
+	if _, err := tree.PathTo(StringID("B")); !errors.Is(err, ErrStale) {
+		t.Fatalf("Expected ErrStale but %v", err)
+	}
+}
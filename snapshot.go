@@ -0,0 +1,64 @@
+package goraph
+
+// GraphSnapshot is an immutable copy of a graph taken at one point in
+// time. Its methods do not lock, and later changes to the graph it was
+// taken from do not affect it.
+type GraphSnapshot struct {
+	id           string
+	nodes        map[ID]Node
+	nodeChildren map[ID]map[ID]float64
+}
+
+func (g *graph) Snapshot() GraphSnapshot {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	s := GraphSnapshot{
+		id:           g.id,
+		nodes:        make(map[ID]Node, len(g.nodes)),
+		nodeChildren: make(map[ID]map[ID]float64, len(g.nodeChildren)),
+	}
+	for id, nd := range g.nodes {
+		s.nodes[id] = copyNode(nd)
+	}
+	for id1, tmap := range g.nodeChildren {
+		cmap := make(map[ID]float64, len(tmap))
+		for id2, weight := range tmap {
+			cmap[id2] = weight
+		}
+		s.nodeChildren[id1] = cmap
+	}
+	return s
+}
+
+// ID returns the ID of the graph the snapshot was taken from.
+func (s GraphSnapshot) ID() ID {
+	return StringID(s.id)
+}
+
+// NodeCount returns the total number of nodes.
+func (s GraphSnapshot) NodeCount() int {
+	return len(s.nodes)
+}
+
+// Nodes returns a copy of the map from node ID to Node.
+// The nodes are shared between calls and must not be modified.
+func (s GraphSnapshot) Nodes() map[ID]Node {
+	rs := make(map[ID]Node, len(s.nodes))
+	for id, nd := range s.nodes {
+		rs[id] = nd
+	}
+	return rs
+}
+
+// Edges returns all edges sorted by source and target IDs.
+func (s GraphSnapshot) Edges() []Edge {
+	rs := []Edge{}
+	for id1, tmap := range s.nodeChildren {
+		for id2, weight := range tmap {
+			rs = append(rs, NewEdge(s.nodes[id1], s.nodes[id2], weight, nil))
+		}
+	}
+	sortEdges(rs)
+	return rs
+}
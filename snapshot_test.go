@@ -0,0 +1,53 @@
+package goraph
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestGraph_Snapshot(t *testing.T) {
+	g := NewGraph()
+	for i := 0; i < 100; i++ {
+		g.AddNode(NewNode(fmt.Sprintf("n%d", i), map[string]string{"i": fmt.Sprint(i)}))
+	}
+	for i := 1; i < 100; i++ {
+		g.AddEdge(StringID("n0"), StringID(fmt.Sprintf("n%d", i)), 1)
+	}
+	s := g.Snapshot()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			g.AddEdge(StringID(fmt.Sprintf("n%d", i%100)), StringID(fmt.Sprintf("n%d", (i+1)%100)), 1)
+			g.AddNode(NewNode(fmt.Sprintf("m%d", i), nil))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if s.NodeCount() != 100 {
+			t.Fatalf("Expected 100 nodes but %d", s.NodeCount())
+		}
+		if n := len(s.Nodes()); n != 100 {
+			t.Fatalf("Expected 100 nodes but %d", n)
+		}
+		edges := s.Edges()
+		if len(edges) != 99 {
+			t.Fatalf("Expected 99 edges but %d", len(edges))
+		}
+		for _, edge := range edges {
+			if edge.Source().ID() != StringID("n0") || edge.Weight() != 1 {
+				t.Fatalf("Unexpected edge %s", edge)
+			}
+		}
+	}
+	wg.Wait()
+
+	if s.NodeCount() != 100 || len(s.Edges()) != 99 {
+		t.Fatalf("Expected snapshot unchanged but %d nodes and %d edges", s.NodeCount(), len(s.Edges()))
+	}
+	if g.NodeCount() != 1100 {
+		t.Fatalf("Expected 1100 nodes in the graph but %d", g.NodeCount())
+	}
+}
@@ -1,6 +1,25 @@
 package goraph
 
-import "sync"
+import (
+	"sort"
+	"sync"
+)
+
+// StronglyConnectedComponents returns the strongly connected components
+// of the graph using Tarjan's algorithm. Each component is sorted by ID
+// and the components are ordered by their first ID, so the result is
+// deterministic. A node that is not part of any cycle forms a component
+// of its own.
+func StronglyConnectedComponents(g Graph) [][]ID {
+	scc := Tarjan(g)
+	for _, component := range scc {
+		sort.Sort(idSlice(component))
+	}
+	sort.Slice(scc, func(i, j int) bool {
+		return lessID(scc[i][0], scc[j][0])
+	})
+	return scc
+}
 
 // Tarjan finds the strongly connected components.
 // In the mathematics, a directed graph is "strongly connected"
@@ -76,6 +95,12 @@ func Tarjan(g Graph) [][]ID {
 	return d.result
 }
 
+// IsStronglyConnected checks that Tarjan finds
+// at most one strongly connected component.
+func (g *graph) IsStronglyConnected() bool {
+	return len(Tarjan(g)) <= 1
+}
+
 type tarjanData struct {
 	mu sync.Mutex // guards the following
 
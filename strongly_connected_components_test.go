@@ -3,6 +3,8 @@ package goraph
 import (
 	"fmt"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -39,3 +41,71 @@ func TestGraph_Tarjan_15(t *testing.T) {
 	}
 	fmt.Println("Tarjan graph_15:", scc)
 }
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	data := `
+services:
+  web:
+    auth: 1
+    api: 1
+  api:
+    db: 1
+    cache: 1
+  db:
+    auth: 1
+  auth:
+    api: 1
+  cache:
+    metrics: 1
+`
+	g, err := NewGraphFromYAML(strings.NewReader(data), "services")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scc := StronglyConnectedComponents(g)
+	expected := [][]ID{
+		{StringID("api"), StringID("auth"), StringID("db")},
+		{StringID("cache")},
+		{StringID("metrics")},
+		{StringID("web")},
+	}
+	if !reflect.DeepEqual(scc, expected) {
+		t.Fatalf("Expected %v but %v", expected, scc)
+	}
+}
+
+func TestGraph_IsStronglyConnected(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"graph_00": true,
+		"graph_05": false,
+		"graph_14": false,
+	} {
+		f, err := os.Open("testdata/graph.json")
+		if err != nil {
+			t.Error(err)
+		}
+		defer f.Close()
+		g, err := NewGraphFromJSON(f, name)
+		if err != nil {
+			t.Error(err)
+		}
+		if g.IsStronglyConnected() != expected {
+			t.Errorf("%s | Expected %v", name, expected)
+		}
+	}
+
+	g := NewGraph()
+	if !g.IsStronglyConnected() {
+		t.Error("Expected an empty graph to be strongly connected")
+	}
+	for _, id := range []string{"A", "B", "C", "D"} {
+		g.AddNode(NewNode(id, make(map[string]string)))
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("B"), StringID("A"), 1)
+	g.AddEdge(StringID("C"), StringID("D"), 1)
+	g.AddEdge(StringID("D"), StringID("C"), 1)
+	if g.IsStronglyConnected() {
+		t.Errorf("Expected two islands not to be strongly connected\n%s", g)
+	}
+}
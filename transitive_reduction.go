@@ -0,0 +1,80 @@
+package goraph
+
+// TransitiveReduction returns a new graph with the same nodes and
+// the fewest edges that keep the same reachability. An edge from u
+// to v is removed when v can also be reached from u through a longer
+// path. Kept edges keep their weights, and node properties are copied.
+// It returns ErrCyclic if the graph has a cycle, since the transitive
+// reduction is then not unique.
+// (https://en.wikipedia.org/wiki/Transitive_reduction)
+//
+//	 0. TransitiveReduction(G)
+//	 1.
+//	 2. 	for each vertex u in G:
+//	 3.
+//	 4. 		R = vertices reachable from a child of u
+//	 5. 		    through at least one more edge
+//	 6.
+//	 7. 		for each child vertex v of u:
+//	 8. 			if v is not in R:
+//	 9. 				add edge (u, v) to the result
+//
+func TransitiveReduction(g Graph) (Graph, error) {
+	if _, isDAG := TopologicalSort(g); !isDAG {
+		return nil, ErrCyclic
+	}
+
+	nodes := g.Nodes()
+	rg := newGraph()
+	rg.id = g.ID().String()
+	for id, nd := range nodes {
+		rg.nodes[id] = copyNode(nd)
+	}
+
+	// for each vertex u in G:
+	for u := range nodes {
+		cmap, err := g.ChildNodesOf(u)
+		if err != nil {
+			return nil, err
+		}
+
+		// R = vertices reachable from a child of u
+		//     through at least one more edge
+		reached := make(map[ID]bool)
+		for w := range cmap {
+			stack := []ID{w}
+			for len(stack) != 0 {
+				x := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				xmap, err := g.ChildNodesOf(x)
+				if err != nil {
+					return nil, err
+				}
+				for y := range xmap {
+					if !reached[y] {
+						reached[y] = true
+						stack = append(stack, y)
+					}
+				}
+			}
+		}
+
+		// for each child vertex v of u:
+		for v := range cmap {
+			// if v is not in R:
+			if reached[v] {
+				continue
+			}
+			weight, err := g.EdgeWeight(u, v)
+			if err != nil {
+				return nil, err
+			}
+			// add edge (u, v) to the result
+			if err := rg.AddEdge(u, v, weight); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return rg, nil
+}
@@ -0,0 +1,38 @@
+package goraph
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransitiveReduction(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"A", "B", "C", "D"} {
+		g.AddNode(NewNode(id, map[string]string{"name": id}))
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("B"), StringID("C"), 2)
+	g.AddEdge(StringID("A"), StringID("C"), 3) // implied by A -> B -> C
+	g.AddEdge(StringID("C"), StringID("D"), 4)
+	g.AddEdge(StringID("A"), StringID("D"), 5) // implied by A -> B -> C -> D
+
+	rg, err := TransitiveReduction(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "A -- 1.000 -→ B\nB -- 2.000 -→ C\nC -- 4.000 -→ D\n"
+	if rg.String() != expected {
+		t.Fatalf("Expected\n%s\nbut\n%s", expected, rg)
+	}
+	if rg.NodeCount() != 4 {
+		t.Fatalf("Expected 4 nodes but %d", rg.NodeCount())
+	}
+	if !g.HasEdge(StringID("A"), StringID("C")) {
+		t.Fatal("Expected the original graph to keep the edge from A to C")
+	}
+
+	g.AddEdge(StringID("D"), StringID("A"), 1)
+	if _, err := TransitiveReduction(g); !errors.Is(err, ErrCyclic) {
+		t.Fatalf("Expected ErrCyclic but %v", err)
+	}
+}
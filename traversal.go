@@ -1,5 +1,15 @@
 package goraph
 
+import (
+	"container/heap"
+	"context"
+	"fmt"
+)
+
+// contextCheckInterval is the number of visited nodes
+// between two checks of the context.
+const contextCheckInterval = 64
+
 // BFS does breadth-first search, and returns the list of vertices.
 // (https://en.wikipedia.org/wiki/Breadth-first_search)
 //
@@ -43,7 +53,7 @@ func BFS(g Graph, id ID) []ID {
 				q = append(q, w.ID())  // Q.push(w)
 				visited[w.ID()] = true // label w as visited
 
-				rs = append(rs, w)
+				rs = append(rs, w.ID())
 			}
 		}
 		pmap, _ := g.ParentNodesOf(u)
@@ -61,6 +71,102 @@ func BFS(g Graph, id ID) []ID {
 	return rs
 }
 
+// BFSContext does breadth-first search like BFS, and calls visit with
+// each node and its depth from the starting node. The search stops when
+// visit returns false. It checks ctx every few nodes, and returns
+// ctx.Err() if the context is canceled or times out.
+func BFSContext(ctx context.Context, g Graph, id ID, visit func(Node, int) bool) error {
+	nd, err := g.Node(id)
+	if err != nil {
+		return err
+	}
+
+	q := []ID{id}
+	depth := map[ID]int{id: 0}
+	if !visit(nd, 0) {
+		return nil
+	}
+	cnt := 1
+
+	// while Q is not empty:
+	for len(q) != 0 {
+
+		u := q[0]
+		q = q[1:len(q):len(q)]
+
+		cmap, err := g.ChildNodesOf(u)
+		if err != nil {
+			return err
+		}
+		pmap, err := g.ParentNodesOf(u)
+		if err != nil {
+			return err
+		}
+
+		// for each vertex w adjacent to u:
+		for _, nmap := range []map[ID]Node{cmap, pmap} {
+			for _, w := range nmap {
+				// if w is not visited yet:
+				if _, ok := depth[w.ID()]; ok {
+					continue
+				}
+				q = append(q, w.ID())        // Q.push(w)
+				depth[w.ID()] = depth[u] + 1 // label w as visited
+
+				if !visit(w, depth[w.ID()]) {
+					return nil
+				}
+				cnt++
+				if cnt%contextCheckInterval == 0 {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return ctx.Err()
+}
+
+// BestFirstSearch visits the nodes reachable from start following
+// outgoing edges, always visiting next the discovered node with the
+// smallest score, and calls visit with each node once. The search
+// stops when visit returns false. The order of nodes with the same
+// score is unspecified.
+// (https://en.wikipedia.org/wiki/Best-first_search)
+func BestFirstSearch(g Graph, start ID, score func(n Node) float64, visit func(n Node) bool) error {
+	nd, err := g.Node(start)
+	if err != nil {
+		return err
+	}
+
+	nodes := map[ID]Node{start: nd}
+	minHeap := &nodeDistanceHeap{}
+	heap.Push(minHeap, nodeDistance{id: start, distance: score(nd)})
+
+	for minHeap.Len() != 0 {
+		u := heap.Pop(minHeap).(nodeDistance)
+		if !visit(nodes[u.id]) {
+			return nil
+		}
+
+		cmap, err := g.ChildNodesOf(u.id)
+		if err != nil {
+			return err
+		}
+		for w, wnd := range cmap {
+			// a node is discovered at most once
+			if _, ok := nodes[w]; ok {
+				continue
+			}
+			nodes[w] = wnd
+			heap.Push(minHeap, nodeDistance{id: w, distance: score(wnd)})
+		}
+	}
+	return nil
+}
+
 // DFS does depth-first search, and returns the list of vertices.
 // (https://en.wikipedia.org/wiki/Depth-first_search)
 //
@@ -182,3 +288,122 @@ func dfsRecursion(g Graph, id ID, visited map[ID]bool, rs *[]ID) {
 		}
 	}
 }
+
+// Neighborhood does breadth-first search over the child nodes,
+// stopping after k levels.
+func (g *graph) Neighborhood(id ID, k int) (map[ID]Node, error) {
+	if k < 0 {
+		return nil, fmt.Errorf("k must not be negative but %d", k)
+	}
+	if _, err := g.Node(id); err != nil {
+		return nil, err
+	}
+
+	rs := make(map[ID]Node)
+	visited := make(map[ID]bool)
+	visited[id] = true
+	q := []ID{id}
+
+	for depth := 0; depth < k && len(q) != 0; depth++ {
+		next := []ID{}
+		for _, u := range q {
+			cmap, err := g.ChildNodesOf(u)
+			if err != nil {
+				return nil, err
+			}
+			for w, nd := range cmap {
+				// a cycle back to the starting node
+				if w == id {
+					rs[w] = nd
+				}
+				if !visited[w] {
+					visited[w] = true
+					rs[w] = nd
+					next = append(next, w)
+				}
+			}
+		}
+		q = next
+	}
+
+	return rs, nil
+}
+
+// IsConnected does breadth-first search from any node,
+// and checks that every node has been visited.
+func (g *graph) IsConnected() bool {
+	for id := range g.Nodes() {
+		return len(BFS(g, id)) == g.NodeCount()
+	}
+	return true
+}
+
+// UnreachableFrom does depth-first search over the child nodes,
+// and returns the nodes that have not been visited.
+func (g *graph) UnreachableFrom(root ID) ([]ID, error) {
+	if _, err := g.Node(root); err != nil {
+		return nil, err
+	}
+
+	visited := map[ID]bool{root: true}
+	stack := []ID{root}
+	for len(stack) != 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		cmap, err := g.ChildNodesOf(u)
+		if err != nil {
+			return nil, err
+		}
+		for w := range cmap {
+			if !visited[w] {
+				visited[w] = true
+				stack = append(stack, w)
+			}
+		}
+	}
+
+	rs := []ID{}
+	for _, id := range sortedIDs(g.Nodes()) {
+		if !visited[id] {
+			rs = append(rs, id)
+		}
+	}
+	return rs, nil
+}
+
+// CanReach does depth-first search over the child nodes,
+// and stops as soon as to is visited.
+func (g *graph) CanReach(from, to ID) (bool, error) {
+	if _, err := g.Node(from); err != nil {
+		return false, err
+	}
+	if _, err := g.Node(to); err != nil {
+		return false, err
+	}
+	if from == to {
+		return true, nil
+	}
+
+	visited := map[ID]bool{from: true}
+	stack := []ID{from}
+	for len(stack) != 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		cmap, err := g.ChildNodesOf(u)
+		if err != nil {
+			return false, err
+		}
+		for w := range cmap {
+			if w == to {
+				return true, nil
+			}
+			if !visited[w] {
+				visited[w] = true
+				stack = append(stack, w)
+			}
+		}
+	}
+	return false, nil
+}
@@ -1,8 +1,12 @@
 package goraph
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -21,6 +25,11 @@ func TestGraph_BFS(t *testing.T) {
 	if len(rs) != 8 {
 		t.Errorf("should be 8 vertices but %s", g)
 	}
+	for _, id := range rs {
+		if !g.HasNode(id) {
+			t.Errorf("Expected node IDs but %v (%T)", id, id)
+		}
+	}
 }
 
 func TestGraph_DFS(t *testing.T) {
@@ -56,3 +65,269 @@ func TestGraph_DFSRecursion(t *testing.T) {
 		t.Errorf("should be 8 vertices but %s", g)
 	}
 }
+
+func TestGraph_Neighborhood(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_08")
+	if err != nil {
+		t.Error(err)
+	}
+	for k, expected := range map[int][]string{
+		0: {},
+		1: {"B", "E", "H"},
+		2: {"B", "C", "D", "E", "F", "G", "H", "A"},
+	} {
+		rs, err := g.Neighborhood(StringID("A"), k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rs) != len(expected) {
+			t.Fatalf("k=%d | Expected %v but %v", k, expected, rs)
+		}
+		for _, id := range expected {
+			if _, ok := rs[StringID(id)]; !ok {
+				t.Fatalf("k=%d | Expected %s in %v", k, id, rs)
+			}
+		}
+	}
+
+	// B is not part of any cycle within 2 hops
+	rs, err := g.Neighborhood(StringID("B"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := rs[StringID("B")]; ok {
+		t.Fatalf("Expected B to be excluded but %v", rs)
+	}
+
+	if _, err := g.Neighborhood(StringID("A"), -1); err == nil {
+		t.Fatal("Expected an error for negative k")
+	}
+	if _, err := g.Neighborhood(StringID("X"), 1); err == nil {
+		t.Fatal("Expected an error for unknown node")
+	}
+}
+
+func TestBFSContext(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Error(err)
+	}
+	depth := make(map[ID]int)
+	err = BFSContext(context.Background(), g, StringID("S"), func(nd Node, d int) bool {
+		depth[nd.ID()] = d
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(depth) != 8 {
+		t.Fatalf("should be 8 vertices but %v", depth)
+	}
+	for id, d := range map[string]int{"S": 0, "A": 1, "B": 1, "C": 1, "D": 2, "T": 2, "E": 2, "F": 3} {
+		if depth[StringID(id)] != d {
+			t.Errorf("%s | Expected depth %d but %d", id, d, depth[StringID(id)])
+		}
+	}
+
+	cnt := 0
+	err = BFSContext(context.Background(), g, StringID("S"), func(nd Node, d int) bool {
+		cnt++
+		return cnt < 3
+	})
+	if err != nil || cnt != 3 {
+		t.Fatalf("Expected to stop after 3 vertices but %d %v", cnt, err)
+	}
+}
+
+func TestBFSContext_cancel(t *testing.T) {
+	g := NewGraph()
+	for i := 0; i < 10000; i++ {
+		g.AddNode(NewNode(fmt.Sprintf("%d", i), make(map[string]string)))
+		if i > 0 {
+			g.AddEdge(StringID(fmt.Sprintf("%d", i-1)), StringID(fmt.Sprintf("%d", i)), 1)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cnt := 0
+	err := BFSContext(ctx, g, StringID("0"), func(nd Node, d int) bool {
+		cnt++
+		if cnt == 100 {
+			cancel()
+		}
+		return true
+	})
+	if err != context.Canceled {
+		t.Fatalf("Expected context.Canceled but %v", err)
+	}
+	if cnt >= 10000 {
+		t.Fatalf("Expected the traversal to stop early but visited %d", cnt)
+	}
+}
+
+func TestGraph_IsConnected(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_05")
+	if err != nil {
+		t.Error(err)
+	}
+	if !g.IsConnected() {
+		t.Errorf("Expected graph_05 to be weakly connected\n%s", g)
+	}
+	if g.IsStronglyConnected() {
+		t.Errorf("Expected graph_05 not to be strongly connected\n%s", g)
+	}
+
+	g = NewGraph()
+	if !g.IsConnected() {
+		t.Error("Expected an empty graph to be connected")
+	}
+	for _, id := range []string{"A", "B", "C", "D"} {
+		g.AddNode(NewNode(id, make(map[string]string)))
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 1)
+	g.AddEdge(StringID("C"), StringID("D"), 1)
+	if g.IsConnected() {
+		t.Errorf("Expected two islands not to be connected\n%s", g)
+	}
+	g.AddEdge(StringID("D"), StringID("A"), 1)
+	if !g.IsConnected() {
+		t.Errorf("Expected a connected graph\n%s", g)
+	}
+}
+
+func TestGraph_UnreachableFrom(t *testing.T) {
+	g := NewGraph()
+	for _, id := range []string{"main", "a", "b", "c", "dead", "deader"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	g.AddEdge(StringID("main"), StringID("a"), 1)
+	g.AddEdge(StringID("a"), StringID("b"), 1)
+	g.AddEdge(StringID("b"), StringID("a"), 1)
+	g.AddEdge(StringID("main"), StringID("c"), 1)
+	// an orphaned subcomponent calling into the reachable part
+	g.AddEdge(StringID("dead"), StringID("deader"), 1)
+	g.AddEdge(StringID("deader"), StringID("a"), 1)
+
+	rs, err := g.UnreachableFrom(StringID("main"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rs, []ID{StringID("dead"), StringID("deader")}) {
+		t.Fatalf("Expected [dead deader] but %v", rs)
+	}
+
+	rs, err = g.UnreachableFrom(StringID("c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 5 {
+		t.Fatalf("Expected every node but c but %v", rs)
+	}
+
+	if _, err := g.UnreachableFrom(StringID("X")); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+}
+
+func TestGraph_CanReach(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := NewGraphFromJSON(f, "graph_06")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"B", "F", true},
+		{"A", "G", true},
+		{"A", "F", false},
+		{"F", "B", false},
+		{"A", "A", true},
+		{"F", "F", true},
+		{"F", "G", false},
+	}
+	for _, tt := range tests {
+		ok, err := g.CanReach(StringID(tt.from), StringID(tt.to))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tt.want {
+			t.Fatalf("Expected %v from %s to %s but %v", tt.want, tt.from, tt.to, ok)
+		}
+	}
+
+	if _, err := g.CanReach(StringID("A"), StringID("X")); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+	if _, err := g.CanReach(StringID("X"), StringID("A")); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+}
+
+func TestBestFirstSearch(t *testing.T) {
+	g := NewGraph()
+	scores := map[string]string{"S": "0", "A": "5", "B": "1", "C": "3", "D": "2", "E": "4", "X": "0"}
+	for id, score := range scores {
+		g.AddNode(NewNode(id, map[string]string{"score": score}))
+	}
+	for _, e := range [][2]string{
+		{"S", "A"}, {"S", "B"}, {"B", "C"}, {"B", "D"},
+		{"D", "E"}, {"D", "S"}, {"C", "A"}, {"X", "S"},
+	} {
+		g.AddEdge(StringID(e[0]), StringID(e[1]), 100)
+	}
+	score := func(nd Node) float64 {
+		v := nd.Props()["score"]
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+
+	visited := []ID{}
+	err := BestFirstSearch(g, StringID("S"), score, func(nd Node) bool {
+		visited = append(visited, nd.ID())
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// X is not reachable, and each node is visited once
+	expected := []ID{StringID("S"), StringID("B"), StringID("D"), StringID("C"), StringID("E"), StringID("A")}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Fatalf("Expected %v but %v", expected, visited)
+	}
+
+	visited = visited[:0]
+	BestFirstSearch(g, StringID("S"), score, func(nd Node) bool {
+		visited = append(visited, nd.ID())
+		return nd.ID() != StringID("D")
+	})
+	if !reflect.DeepEqual(visited, expected[:3]) {
+		t.Fatalf("Expected %v but %v", expected[:3], visited)
+	}
+
+	if err := BestFirstSearch(g, StringID("Y"), score, func(Node) bool { return true }); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+}
@@ -0,0 +1,345 @@
+package goraph
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+// Number is the set of weight types of a WeightedGraph. Unsigned
+// integers are left out, since algorithms such as MaxFlow need
+// negative values for residual flows.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// WeightedGraph is a directed graph like the one returned by NewGraph,
+// but with edge weights of type W instead of float64, so that integer
+// weights such as flow capacities stay exact. It is safe for
+// concurrent use. Float64 converts it to a Graph for the algorithms
+// that take one.
+type WeightedGraph[W Number] struct {
+	mu sync.RWMutex // guards the following
+
+	// id is a unique graph identifier
+	id string
+
+	// nodes stores all nodes.
+	nodes map[ID]Node
+
+	// nodeParents maps a Node identifer to sources(parents)
+	// with edge weights.
+	nodeParents map[ID]map[ID]W
+
+	// nodeChildren maps a Node identifer to targets(children)
+	// with edge weights.
+	nodeChildren map[ID]map[ID]W
+}
+
+// NewWeightedGraph returns a new WeightedGraph with the ID.
+func NewWeightedGraph[W Number](id string) *WeightedGraph[W] {
+	return &WeightedGraph[W]{
+		id:           id,
+		nodes:        make(map[ID]Node),
+		nodeParents:  make(map[ID]map[ID]W),
+		nodeChildren: make(map[ID]map[ID]W),
+	}
+}
+
+// ID returns the graph ID.
+func (g *WeightedGraph[W]) ID() ID {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return StringID(g.id)
+}
+
+// NodeCount returns the total number of nodes.
+func (g *WeightedGraph[W]) NodeCount() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return len(g.nodes)
+}
+
+// Node finds the Node. It returns error if not found.
+func (g *WeightedGraph[W]) Node(id ID) (Node, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	nd, ok := g.nodes[id]
+	if !ok {
+		return nil, &NodeNotFoundError{ID: id}
+	}
+	return nd, nil
+}
+
+// HasNode returns true if the node exists in the graph.
+func (g *WeightedGraph[W]) HasNode(id ID) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	_, ok := g.nodes[id]
+	return ok
+}
+
+// Nodes returns a map from node ID to Node.
+func (g *WeightedGraph[W]) Nodes() map[ID]Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	rs := make(map[ID]Node, len(g.nodes))
+	for id, nd := range g.nodes {
+		rs[id] = nd
+	}
+	return rs
+}
+
+// AddNode adds a node to the graph, and returns false
+// if the node already existed in the graph.
+func (g *WeightedGraph[W]) AddNode(nd Node) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.nodes[nd.ID()]; ok {
+		return false
+	}
+	g.nodes[nd.ID()] = nd
+	return true
+}
+
+// DeleteNode deletes a node and its edges from the graph,
+// and returns false if the node does not exist.
+func (g *WeightedGraph[W]) DeleteNode(id ID) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.nodes[id]; !ok {
+		return false
+	}
+	delete(g.nodes, id)
+
+	for cid := range g.nodeChildren[id] {
+		delete(g.nodeParents[cid], id)
+	}
+	delete(g.nodeChildren, id)
+	for pid := range g.nodeParents[id] {
+		delete(g.nodeChildren[pid], id)
+	}
+	delete(g.nodeParents, id)
+	return true
+}
+
+// AddEdge adds an edge from id1 to id2 with the weight.
+// It adds to the weight if the edge already exists.
+func (g *WeightedGraph[W]) AddEdge(id1, id2 ID, weight W) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.unsafeSetEdge(id1, id2, weight, true)
+}
+
+// ReplaceEdge replaces an edge from id1 to id2 with the weight.
+func (g *WeightedGraph[W]) ReplaceEdge(id1, id2 ID, weight W) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.unsafeSetEdge(id1, id2, weight, false)
+}
+
+func (g *WeightedGraph[W]) unsafeSetEdge(id1, id2 ID, weight W, add bool) error {
+	if _, ok := g.nodes[id1]; !ok {
+		return &NodeNotFoundError{ID: id1}
+	}
+	if _, ok := g.nodes[id2]; !ok {
+		return &NodeNotFoundError{ID: id2}
+	}
+
+	if add {
+		weight += g.nodeChildren[id1][id2]
+	}
+	if _, ok := g.nodeChildren[id1]; !ok {
+		g.nodeChildren[id1] = make(map[ID]W)
+	}
+	g.nodeChildren[id1][id2] = weight
+	if _, ok := g.nodeParents[id2]; !ok {
+		g.nodeParents[id2] = make(map[ID]W)
+	}
+	g.nodeParents[id2][id1] = weight
+	return nil
+}
+
+// DeleteEdge deletes an edge from id1 to id2. It returns
+// error matching ErrEdgeNotFound if there is no such edge.
+func (g *WeightedGraph[W]) DeleteEdge(id1, id2 ID) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.nodes[id1]; !ok {
+		return &NodeNotFoundError{ID: id1}
+	}
+	if _, ok := g.nodes[id2]; !ok {
+		return &NodeNotFoundError{ID: id2}
+	}
+	if _, ok := g.nodeChildren[id1][id2]; !ok {
+		return &EdgeNotFoundError{Source: id1, Target: id2}
+	}
+	delete(g.nodeChildren[id1], id2)
+	delete(g.nodeParents[id2], id1)
+	return nil
+}
+
+// HasEdge returns true if there is an edge from id1 to id2.
+func (g *WeightedGraph[W]) HasEdge(id1, id2 ID) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	_, ok := g.nodeChildren[id1][id2]
+	return ok
+}
+
+// EdgeWeight returns the weight from id1 to id2.
+func (g *WeightedGraph[W]) EdgeWeight(id1, id2 ID) (W, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if _, ok := g.nodes[id1]; !ok {
+		return 0, &NodeNotFoundError{ID: id1}
+	}
+	if _, ok := g.nodes[id2]; !ok {
+		return 0, &NodeNotFoundError{ID: id2}
+	}
+	if v, ok := g.nodeChildren[id1][id2]; ok {
+		return v, nil
+	}
+	return 0, &EdgeNotFoundError{Source: id1, Target: id2}
+}
+
+// ParentNodesOf returns the map of parent Nodes.
+func (g *WeightedGraph[W]) ParentNodesOf(id ID) (map[ID]Node, error) {
+	return g.neighbors(id, g.nodeParents)
+}
+
+// ChildNodesOf returns the map of child Nodes.
+func (g *WeightedGraph[W]) ChildNodesOf(id ID) (map[ID]Node, error) {
+	return g.neighbors(id, g.nodeChildren)
+}
+
+func (g *WeightedGraph[W]) neighbors(id ID, adj map[ID]map[ID]W) (map[ID]Node, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if _, ok := g.nodes[id]; !ok {
+		return nil, &NodeNotFoundError{ID: id}
+	}
+	rs := make(map[ID]Node, len(adj[id]))
+	for n := range adj[id] {
+		rs[n] = g.nodes[n]
+	}
+	return rs, nil
+}
+
+// EachEdge calls fn for each edge while holding the read lock,
+// until fn returns false. fn must not modify the graph.
+func (g *WeightedGraph[W]) EachEdge(fn func(src, tgt ID, weight W) bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for id1, cmap := range g.nodeChildren {
+		for id2, weight := range cmap {
+			if !fn(id1, id2, weight) {
+				return
+			}
+		}
+	}
+}
+
+// Float64 returns a new Graph with the same nodes and the weights
+// converted to float64. Node properties are copied.
+func (g *WeightedGraph[W]) Float64() Graph {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	fg := newGraph()
+	fg.id = g.id
+	for id, nd := range g.nodes {
+		fg.nodes[id] = copyNode(nd)
+	}
+	for id1, cmap := range g.nodeChildren {
+		for id2, weight := range cmap {
+			fg.unsafeAddEdge(id1, id2, float64(weight), nil)
+		}
+	}
+	return fg
+}
+
+// String describes the graph with one line per edge,
+// sorted by source and target IDs.
+func (g *WeightedGraph[W]) String() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	buf := new(bytes.Buffer)
+	for _, id1 := range sortedIDs(g.nodes) {
+		cmap := make(map[ID]Node, len(g.nodeChildren[id1]))
+		for id2 := range g.nodeChildren[id1] {
+			cmap[id2] = g.nodes[id2]
+		}
+		for _, id2 := range sortedIDs(cmap) {
+			fmt.Fprintf(buf, "%s -- %v -→ %s\n", id1, g.nodeChildren[id1][id2], id2)
+		}
+	}
+	return buf.String()
+}
+
+// MaxFlow returns the maximum flow from source to sink like the
+// MaxFlow function, computed exactly in the weight type.
+func (g *WeightedGraph[W]) MaxFlow(source, sink ID) (W, map[string]map[string]W, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if _, ok := g.nodes[source]; !ok {
+		return 0, nil, &NodeNotFoundError{ID: source}
+	}
+	if _, ok := g.nodes[sink]; !ok {
+		return 0, nil, &NodeNotFoundError{ID: sink}
+	}
+	if source == sink {
+		return 0, nil, fmt.Errorf("source and sink must be different but both are %s", source)
+	}
+
+	capacity := make(map[ID]map[ID]W, len(g.nodes))
+	neighbors := make(map[ID][]ID, len(g.nodes))
+	for u := range g.nodes {
+		capacity[u] = make(map[ID]W)
+		nmap := make(map[ID]Node)
+		for v, weight := range g.nodeChildren[u] {
+			if weight < 0 {
+				return 0, nil, fmt.Errorf("capacity from %s to %s must not be negative but %v", u, v, weight)
+			}
+			capacity[u][v] = weight
+			nmap[v] = g.nodes[v]
+		}
+		for v := range g.nodeParents[u] {
+			nmap[v] = g.nodes[v]
+		}
+		neighbors[u] = sortedIDs(nmap)
+	}
+
+	maxFlow, flow := edmondsKarp(capacity, neighbors, source, sink)
+
+	rs := make(map[string]map[string]W)
+	for u, cmap := range capacity {
+		if len(cmap) == 0 {
+			continue
+		}
+		rs[u.String()] = make(map[string]W)
+		for v := range cmap {
+			rs[u.String()][v.String()] = 0
+			if flow[u][v] > 0 {
+				rs[u.String()][v.String()] = flow[u][v]
+			}
+		}
+	}
+	return maxFlow, rs, nil
+}
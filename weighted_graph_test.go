@@ -0,0 +1,115 @@
+package goraph
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestWeightedGraph_MaxFlow(t *testing.T) {
+	f, err := os.Open("testdata/graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	fg, err := NewGraphFromJSON(f, "graph_00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewWeightedGraph[int]("graph_00")
+	for _, nd := range fg.Nodes() {
+		g.AddNode(nd)
+	}
+	for _, edge := range fg.Edges() {
+		if err := g.AddEdge(edge.Source().ID(), edge.Target().ID(), int(edge.Weight())); err != nil {
+			t.Fatal(err)
+		}
+	}
+	maxFlow, flow, err := g.MaxFlow(StringID("S"), StringID("T"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxFlow != 85 {
+		t.Fatalf("Expected 85 but %d", maxFlow)
+	}
+	out := 0
+	for _, f := range flow["S"] {
+		out += f
+	}
+	if out != 85 {
+		t.Fatalf("Expected 85 out of S but %d", out)
+	}
+	if !Equal(g.Float64(), fg) {
+		t.Fatalf("Expected %s but %s", fg, g.Float64())
+	}
+
+	// capacities that float64 cannot represent exactly
+	big := NewWeightedGraph[int64]("big")
+	for _, id := range []string{"S", "A", "T"} {
+		big.AddNode(NewNode(id, nil))
+	}
+	big.AddEdge(StringID("S"), StringID("A"), 1<<60+1)
+	big.AddEdge(StringID("A"), StringID("T"), 1<<60+1)
+	big.AddEdge(StringID("S"), StringID("T"), 1)
+	bigFlow, _, err := big.MaxFlow(StringID("S"), StringID("T"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bigFlow != 1<<60+2 {
+		t.Fatalf("Expected %d but %d", int64(1<<60+2), bigFlow)
+	}
+
+	big.AddEdge(StringID("A"), StringID("S"), -1)
+	if _, _, err := big.MaxFlow(StringID("S"), StringID("T")); err == nil {
+		t.Fatal("Expected error for a negative capacity")
+	}
+	if _, _, err := big.MaxFlow(StringID("S"), StringID("X")); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+}
+
+func TestWeightedGraph(t *testing.T) {
+	g := NewWeightedGraph[int32]("g")
+	for _, id := range []string{"A", "B", "C"} {
+		g.AddNode(NewNode(id, nil))
+	}
+	g.AddEdge(StringID("A"), StringID("B"), 2)
+	g.AddEdge(StringID("A"), StringID("B"), 3)
+	g.ReplaceEdge(StringID("B"), StringID("C"), 7)
+	if v, err := g.EdgeWeight(StringID("A"), StringID("B")); err != nil || v != 5 {
+		t.Fatalf("Expected weight 5 but %v %v", v, err)
+	}
+	if err := g.AddEdge(StringID("A"), StringID("X"), 1); !errors.Is(err, ErrNodeNotFound) {
+		t.Fatalf("Expected ErrNodeNotFound but %v", err)
+	}
+	if s := g.String(); s != "A -- 5 -→ B\nB -- 7 -→ C\n" {
+		t.Fatalf("Unexpected output\n%s", s)
+	}
+
+	if err := g.DeleteEdge(StringID("C"), StringID("A")); !errors.Is(err, ErrEdgeNotFound) {
+		t.Fatalf("Expected ErrEdgeNotFound but %v", err)
+	}
+	if err := g.DeleteEdge(StringID("B"), StringID("C")); err != nil {
+		t.Fatal(err)
+	}
+	if g.HasEdge(StringID("B"), StringID("C")) {
+		t.Fatalf("Expected B → C to be deleted but %s", g)
+	}
+
+	g.DeleteNode(StringID("B"))
+	if g.NodeCount() != 2 || g.HasEdge(StringID("A"), StringID("B")) {
+		t.Fatalf("Expected B to be deleted but %s", g)
+	}
+	cnt := 0
+	g.EachEdge(func(src, tgt ID, weight int32) bool {
+		cnt++
+		return true
+	})
+	if cnt != 0 {
+		t.Fatalf("Expected no edges but %d", cnt)
+	}
+	if cmap, err := g.ChildNodesOf(StringID("A")); err != nil || len(cmap) != 0 {
+		t.Fatalf("Expected no children but %v %v", cmap, err)
+	}
+}